@@ -0,0 +1,154 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Difficulties that may also be written by name in a problem
+// source. Higher difficulties are harder; a zero Difficulty is
+// unrated.
+const (
+	DifficultyEasy   = 1
+	DifficultyMedium = 2
+	DifficultyHard   = 3
+)
+
+// difficultyNames maps each name a difficulty may be written as
+// to its value.
+var difficultyNames = map[string]int{
+	"easy":   DifficultyEasy,
+	"medium": DifficultyMedium,
+	"hard":   DifficultyHard,
+}
+
+// ParseDifficulty returns the difficulty written either as a
+// name, e.g. "hard", or as a positive whole number. An empty
+// difficulty is zero.
+func ParseDifficulty(text string) (int, error) {
+
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return 0, nil
+	}
+	if d, ok := difficultyNames[text]; ok {
+		return d, nil
+	}
+
+	d, err := strconv.Atoi(text)
+	if err != nil || d < 1 {
+		return 0, fmt.Errorf("invalid difficulty %q (expected easy, medium, hard or a positive number)", text)
+	}
+
+	return d, nil
+}
+
+// Policy chooses the order in which a Session asks its Problems.
+// A Quiz without a Policy asks them in order.
+type Policy interface {
+	// Choose returns the index within remaining of the Problem
+	// to ask next, given the Answers recorded so far, or -1 to
+	// end the quiz.
+	Choose(remaining []Problem, answers []Answer) int
+}
+
+// ADAPTIVE_STEP is how far a single answer can move the rating
+// estimated by Adaptive.
+const ADAPTIVE_STEP = 0.6
+
+// Adaptive is a Policy that asks the remaining Problem whose
+// Difficulty is closest to the user's estimated skill rating.
+//
+// The rating is on the scale of Difficulty: a user is expected
+// to answer half of the questions at their rating correctly. It
+// rises after correct answers and falls after incorrect ones, by
+// more the more surprising the answer was, so the questions get
+// harder or easier as the user's recent answers dictate. Slow
+// answers count for less than quick ones.
+type Adaptive struct {
+	// Start is the rating assumed before the first answer.
+	Start float64
+
+	// Target is the response time of a confident answer to a
+	// question without a TimeLimit. Answers to questions with a
+	// TimeLimit are expected within half of it.
+	Target time.Duration
+}
+
+// NewAdaptive returns an Adaptive Policy that starts with the
+// easiest questions. Set the Quiz's Questions to draw only some
+// of its Problems.
+func NewAdaptive() *Adaptive {
+	return &Adaptive{
+		Start:  DifficultyEasy,
+		Target: 5 * time.Second,
+	}
+}
+
+// Choose returns the index of the remaining Problem nearest in
+// Difficulty to the rating, the earliest of any that are equally
+// near.
+func (a *Adaptive) Choose(remaining []Problem, answers []Answer) int {
+
+	if len(remaining) == 0 {
+		return -1
+	}
+
+	rating := a.Rating(answers)
+	best := 0
+	for i, p := range remaining {
+		if math.Abs(difficultyOf(p)-rating) < math.Abs(difficultyOf(remaining[best])-rating) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// Rating returns the skill rating estimated from the Answers,
+// taken in order.
+func (a *Adaptive) Rating(answers []Answer) float64 {
+
+	rating := a.Start
+	for _, answer := range answers {
+		expected := 1 / (1 + math.Exp(difficultyOf(answer.Problem)-rating))
+		rating += ADAPTIVE_STEP * (a.performance(answer) - expected)
+	}
+
+	return rating
+}
+
+// performance scores an answer from 0 to 1 by its credit,
+// reduced by up to half when it took longer than the target.
+func (a *Adaptive) performance(answer Answer) float64 {
+
+	if answer.TimedOut {
+		return 0
+	}
+
+	target := a.Target
+	if limit := answer.Problem.TimeLimit; limit > 0 {
+		target = limit / 2
+	}
+
+	speed := 1.0
+	if target > 0 && answer.Latency > target {
+		speed = math.Max(0.5, float64(target)/float64(answer.Latency))
+	}
+
+	return answer.Credit * speed
+}
+
+// difficultyOf returns the Difficulty of the Problem, counting
+// an unrated Problem as DifficultyMedium.
+func difficultyOf(p Problem) float64 {
+
+	if p.Difficulty == 0 {
+		return DifficultyMedium
+	}
+
+	return float64(p.Difficulty)
+}
@@ -0,0 +1,289 @@
+package quiz
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestNames are the names a Bundle's manifest may be written
+// under, in order of preference.
+var manifestNames = []string{"manifest.yaml", "manifest.yml", "manifest.json"}
+
+// Bundle is a set of problem sources, or banks, shared together
+// as a directory or a zip archive. A manifest at its root, e.g.
+//
+//	name: General knowledge
+//	version: "2"
+//	banks:
+//	  - path: arithmetic.csv
+//	    category: arithmetic
+//	  - path: capitals.yaml
+//	    name: capitals
+//	    tags: [geography]
+//
+// lists the banks along with their metadata. It is written in
+// YAML (or JSON) and named after one of manifestNames.
+type Bundle struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+	Version     string `yaml:"version" json:"version"`
+	Banks       []Bank `yaml:"banks" json:"banks"`
+
+	path   string
+	fsys   fs.FS
+	closer io.Closer
+}
+
+// Bank is a problem source listed in a Bundle's manifest.
+type Bank struct {
+	// Path is the slash-separated path of the source within the
+	// Bundle.
+	Path string `yaml:"path" json:"path"`
+
+	// Name selects the bank from the Bundle. It defaults to the
+	// file name of its Path without the extension.
+	Name string `yaml:"name" json:"name"`
+
+	// Format is the format of the source. It defaults to the
+	// format of the Path's extension.
+	Format string `yaml:"format" json:"format"`
+
+	Description string `yaml:"description" json:"description"`
+
+	// Category is given to the bank's Problems that do not have
+	// one, and Tags are added to all of them.
+	Category string   `yaml:"category" json:"category"`
+	Tags     []string `yaml:"tags" json:"tags"`
+}
+
+// isBundle reports whether the path is of a directory or a zip
+// archive, which are loaded as Bundles.
+func isBundle(p string) bool {
+
+	if strings.EqualFold(filepath.Ext(p), ".zip") {
+		return true
+	}
+	info, err := os.Stat(p)
+
+	return err == nil && info.IsDir()
+}
+
+// OpenBundle opens the Bundle in the directory or zip archive at
+// the specified path and reads its manifest. A zip archive may
+// hold the Bundle within a single top-level directory.
+func OpenBundle(p string) (*Bundle, error) {
+
+	b := &Bundle{path: p}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		b.fsys = os.DirFS(p)
+	} else {
+		zr, err := zip.OpenReader(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", p, err)
+		}
+		b.fsys, b.closer = zr, zr
+		if sub, ok := singleDir(zr); ok {
+			b.fsys = sub
+		}
+	}
+
+	if err := b.readManifest(); err != nil {
+		b.Close()
+		return nil, fmt.Errorf("%s: %v", p, err)
+	}
+
+	return b, nil
+}
+
+// singleDir returns the top-level directory of the file system
+// if it holds nothing else and has no manifest of its own.
+func singleDir(fsys fs.FS) (fs.FS, bool) {
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return nil, false
+	}
+
+	sub, err := fs.Sub(fsys, entries[0].Name())
+	if err != nil {
+		return nil, false
+	}
+
+	return sub, true
+}
+
+// readManifest decodes the Bundle's manifest and fills in the
+// defaults of its Banks.
+func (b *Bundle) readManifest() error {
+
+	var data []byte
+	var name string
+	for _, name = range manifestNames {
+		var err error
+		data, err = fs.ReadFile(b.fsys, name)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if data == nil {
+		return fmt.Errorf("missing manifest (expected one of %s)", strings.Join(manifestNames, ", "))
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(b); err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	if len(b.Banks) == 0 {
+		return fmt.Errorf("%s: no banks listed", name)
+	}
+
+	names := map[string]bool{}
+	for i := range b.Banks {
+		bank := &b.Banks[i]
+		if !fs.ValidPath(bank.Path) || bank.Path == "." {
+			return fmt.Errorf("%s: invalid bank path %q", name, bank.Path)
+		}
+		if bank.Name == "" {
+			bank.Name = strings.TrimSuffix(path.Base(bank.Path), path.Ext(bank.Path))
+		}
+		if names[bank.Name] {
+			return fmt.Errorf("%s: duplicate bank name %q", name, bank.Name)
+		}
+		names[bank.Name] = true
+	}
+
+	return nil
+}
+
+// Close releases the zip archive the Bundle was opened from, if
+// any.
+func (b *Bundle) Close() error {
+
+	if b.closer == nil {
+		return nil
+	}
+
+	return b.closer.Close()
+}
+
+// Load parses the Problems of the named banks, or of every bank
+// if none are named. Malformed records are reported together as
+// ParseErrors alongside the Problems that could be parsed.
+func (b *Bundle) Load(names ...string) ([]Problem, error) {
+
+	banks := b.Banks
+	if len(names) > 0 {
+		banks = nil
+		for _, name := range names {
+			bank, ok := b.Bank(name)
+			if !ok {
+				return nil, fmt.Errorf("%s: no bank named %q", b.path, name)
+			}
+			banks = append(banks, bank)
+		}
+	}
+
+	var problems []Problem
+	var errs ParseErrors
+	for _, bank := range banks {
+		p, err := b.LoadBank(bank)
+		var parseErrs ParseErrors
+		if errors.As(err, &parseErrs) {
+			errs = append(errs, parseErrs...)
+		} else if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p...)
+	}
+
+	return problems, errs.errorOrNil()
+}
+
+// Bank returns the bank with the specified name.
+func (b *Bundle) Bank(name string) (Bank, bool) {
+
+	for _, bank := range b.Banks {
+		if bank.Name == name {
+			return bank, true
+		}
+	}
+
+	return Bank{}, false
+}
+
+// LoadBank parses the Problems of a bank within the Bundle and
+// applies its Category and Tags.
+func (b *Bundle) LoadBank(bank Bank) ([]Problem, error) {
+
+	name := b.path + "/" + bank.Path
+
+	format := bank.Format
+	if format == "" {
+		var err error
+		if format, err = FormatFromPath(name); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := b.fsys.Open(bank.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", b.path, err)
+	}
+	defer f.Close()
+
+	// Media are kept relative to the bank, but are served from
+	// the root of the Bundle.
+	problems, err := Load(name, f, format)
+	resolveMedia(problems, func(ref string) string {
+		return path.Join(path.Dir(bank.Path), ref)
+	})
+	for i := range problems {
+		if problems[i].Category == "" {
+			problems[i].Category = bank.Category
+		}
+		problems[i].Tags = append(problems[i].Tags, bank.Tags...)
+	}
+
+	return problems, err
+}
+
+// loadBundle parses the Problems of the named bank within the
+// Bundle at the specified path, or of every bank if the name is
+// empty. The source names the Bundle in any errors.
+func loadBundle(source string, p string, bank string) ([]Problem, error) {
+
+	b, err := OpenBundle(p)
+	if err != nil {
+		if source != p {
+			err = fmt.Errorf("%s: %v", source, err)
+		}
+		return nil, err
+	}
+	defer b.Close()
+	b.path = strings.TrimSuffix(source, "#"+bank)
+
+	if bank == "" {
+		return b.Load()
+	}
+
+	return b.Load(bank)
+}
@@ -0,0 +1,171 @@
+package quiz
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testManifest = `name: General knowledge
+version: "2"
+banks:
+  - path: arithmetic.csv
+    category: arithmetic
+  - path: geography/capitals.csv
+    name: capitals
+    tags: [geography]
+`
+
+var testBundle = map[string]string{
+	"manifest.yaml":          testManifest,
+	"arithmetic.csv":         "5+5,10\n1+1,2\n",
+	"geography/capitals.csv": "capital of France,Paris\n",
+}
+
+// writeBundleDir writes the files of a Bundle to a new directory
+// and returns its path.
+func writeBundleDir(t *testing.T, files map[string]string) string {
+
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// writeBundleZip writes the files of a Bundle to a zip archive,
+// within the top-level directory if it is not empty, and returns
+// its path.
+func writeBundleZip(t *testing.T, top string, files map[string]string) string {
+
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "bundle.zip")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		if top != "" {
+			name = top + "/" + name
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func checkBundleProblems(t *testing.T, problems []Problem) {
+
+	t.Helper()
+	if len(problems) != 3 {
+		t.Fatalf("got %d problems, want 3", len(problems))
+	}
+	if p := problems[0]; p.Category != "arithmetic" || len(p.Tags) != 0 {
+		t.Errorf("got the category %q and tags %v, want arithmetic without tags", p.Category, p.Tags)
+	}
+	if p := problems[2]; p.Question != "capital of France" || len(p.Tags) != 1 || p.Tags[0] != "geography" {
+		t.Errorf("got %q with the tags %v, want the capital of France tagged geography", p.Question, p.Tags)
+	}
+}
+
+func TestOpenBundleDir(t *testing.T) {
+
+	dir := writeBundleDir(t, testBundle)
+
+	b, err := OpenBundle(dir)
+	if err != nil {
+		t.Fatalf("OpenBundle: %v", err)
+	}
+	defer b.Close()
+
+	if b.Name != "General knowledge" || b.Version != "2" || len(b.Banks) != 2 {
+		t.Errorf("got the manifest %+v", *b)
+	}
+	if bank, ok := b.Bank("arithmetic"); !ok || bank.Path != "arithmetic.csv" {
+		t.Errorf("got the bank %+v, want arithmetic named after its path", bank)
+	}
+
+	problems, err := LoadSource(dir, "", nil)
+	if err != nil {
+		t.Fatalf("LoadSource: %v", err)
+	}
+	checkBundleProblems(t, problems)
+}
+
+func TestOpenBundleZip(t *testing.T) {
+
+	for _, top := range []string{"", "general"} {
+		p := writeBundleZip(t, top, testBundle)
+
+		problems, err := LoadSource(p, "", nil)
+		if err != nil {
+			t.Fatalf("LoadSource with the top-level directory %q: %v", top, err)
+		}
+		checkBundleProblems(t, problems)
+	}
+}
+
+func TestLoadSourceBank(t *testing.T) {
+
+	p := writeBundleZip(t, "", testBundle)
+
+	problems, err := LoadSource(p+"#capitals", "", nil)
+	if err != nil {
+		t.Fatalf("LoadSource: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Answer != "Paris" {
+		t.Errorf("got %+v, want only the capitals bank", problems)
+	}
+
+	_, err = LoadSource(p+"#history", "", nil)
+	if err == nil || !strings.Contains(err.Error(), `no bank named "history"`) {
+		t.Errorf("got error %v for an unknown bank", err)
+	}
+}
+
+func TestBundleManifestErrors(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		manifest string
+		err      string
+	}{
+		{"missing", "", "missing manifest"},
+		{"no banks", "name: empty\n", "no banks listed"},
+		{"unknown field", "banks:\n  - path: a.csv\n    weight: 2\n", "field weight not found"},
+		{"invalid path", "banks:\n  - path: ../a.csv\n", `invalid bank path "../a.csv"`},
+		{"duplicate name", "banks:\n  - path: a.csv\n  - path: b/a.csv\n", `duplicate bank name "a"`},
+	}
+
+	for _, test := range tests {
+		files := map[string]string{"a.csv": "5+5,10\n"}
+		if test.manifest != "" {
+			files["manifest.yaml"] = test.manifest
+		}
+
+		_, err := OpenBundle(writeBundleDir(t, files))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want one containing %q", test.name, err, test.err)
+		}
+	}
+}
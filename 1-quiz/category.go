@@ -0,0 +1,176 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HasTag reports whether the Problem is labelled with the tag,
+// once both are stripped of formatting. A Problem's Category
+// counts as one of its tags.
+func (p Problem) HasTag(tag string) bool {
+
+	tag = normalize(tag)
+	if p.Category != "" && normalize(p.Category) == tag {
+		return true
+	}
+	for _, t := range p.Tags {
+		if normalize(t) == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterTags returns the problems that have at least one of the
+// included tags, or every problem if none are included, leaving
+// out those that have any of the excluded tags.
+func FilterTags(problems []Problem, include []string, exclude []string) []Problem {
+
+	hasAny := func(p Problem, tags []string) bool {
+		for _, tag := range tags {
+			if p.HasTag(tag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var data []Problem
+	for _, p := range problems {
+		if len(include) > 0 && !hasAny(p, include) {
+			continue
+		}
+		if hasAny(p, exclude) {
+			continue
+		}
+		data = append(data, p)
+	}
+
+	return data
+}
+
+// ParseQuotas parses quotas written as "category=n", e.g.
+// "arithmetic=5", into the number of problems allowed from each
+// category.
+func ParseQuotas(specs []string) (map[string]int, error) {
+
+	quotas := map[string]int{}
+	for _, spec := range specs {
+
+		i := strings.LastIndex(spec, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid quota %q (expected category=n)", spec)
+		}
+		category := strings.TrimSpace(spec[:i])
+		n, err := strconv.Atoi(strings.TrimSpace(spec[i+1:]))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid quota %q (expected a number of questions)", spec)
+		}
+
+		quotas[normalize(category)] = n
+	}
+
+	return quotas, nil
+}
+
+// CheckQuotas returns an error naming the first category of the
+// quotas, in alphabetical order, that none of the problems has.
+func CheckQuotas(problems []Problem, quotas map[string]int) error {
+
+	categories := map[string]bool{}
+	for _, p := range problems {
+		categories[normalize(p.Category)] = true
+	}
+
+	var unknown []string
+	for category := range quotas {
+		if !categories[category] {
+			unknown = append(unknown, category)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	return fmt.Errorf("no questions match the category %q of -quota", unknown[0])
+}
+
+// ApplyQuotas returns the problems with no more of each category
+// than its quota, choosing which to keep psuedo-randomly using
+// the specified source. Categories without a quota are kept in
+// full, and the problems keep their relative order.
+func ApplyQuotas(problems []Problem, quotas map[string]int, rng *rand.Rand) []Problem {
+
+	var order []string
+	indices := map[string][]int{}
+	for i, p := range problems {
+		category := normalize(p.Category)
+		if _, ok := indices[category]; !ok {
+			order = append(order, category)
+		}
+		indices[category] = append(indices[category], i)
+	}
+
+	var kept []int
+	for _, category := range order {
+		quota, ok := quotas[category]
+		if !ok || quota >= len(indices[category]) {
+			kept = append(kept, indices[category]...)
+			continue
+		}
+		for _, j := range rng.Perm(len(indices[category]))[:quota] {
+			kept = append(kept, indices[category][j])
+		}
+	}
+	sort.Ints(kept)
+
+	data := make([]Problem, len(kept))
+	for i, index := range kept {
+		data[i] = problems[index]
+	}
+
+	return data
+}
+
+// CategoryScore is the part of a Result earned on the questions
+// of a single Category.
+type CategoryScore struct {
+	Category string
+	Correct  int
+	Total    int
+	Score    float64
+	MaxScore float64
+}
+
+// ByCategory breaks the Result down by the Category of each
+// question that was asked, in the order each Category was first
+// asked.
+func (r Result) ByCategory() []CategoryScore {
+
+	var scores []CategoryScore
+	index := map[string]int{}
+	for _, a := range r.Answers {
+
+		i, ok := index[a.Problem.Category]
+		if !ok {
+			i = len(scores)
+			index[a.Problem.Category] = i
+			scores = append(scores, CategoryScore{Category: a.Problem.Category})
+		}
+
+		if a.Correct {
+			scores[i].Correct++
+		}
+		scores[i].Total++
+		scores[i].Score += a.Points
+		scores[i].MaxScore += a.Problem.Value()
+	}
+
+	return scores
+}
@@ -0,0 +1,24 @@
+package quiz
+
+import "time"
+
+// Clock provides the passage of time to a running Quiz so that
+// the timer can be replaced, e.g. when embedding the quiz.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is a Clock backed by the time package.
+type RealClock struct{}
+
+// Now returns the current local time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the
+// current time on the returned channel.
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,380 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	RegisterFormat("generate", LoadGenerator, ".gen")
+}
+
+// GeneratorSpec describes a set of arithmetic problems to be
+// generated, e.g.
+//
+//	seed: 42
+//	levels:
+//	  - difficulty: easy
+//	    count: 5
+//	  - difficulty: hard
+//	    count: 5
+//	    operators: ["*", "/"]
+//
+// Each of the Levels is generated in turn, inheriting any field
+// it does not set from the spec. A spec without Levels is itself
+// the only level. Fields that are still unset are taken from the
+// preset of the Difficulty, and then from DEFAULT_GENERATOR.
+type GeneratorSpec struct {
+	// Seed makes the problems reproducible. A zero Seed is chosen
+	// from the current time.
+	Seed int64 `yaml:"seed" json:"seed"`
+
+	Count      int      `yaml:"count" json:"count"`
+	Difficulty string   `yaml:"difficulty" json:"difficulty"`
+	Operators  []string `yaml:"operators" json:"operators"`
+	Min        *int     `yaml:"min" json:"min"`
+	Max        *int     `yaml:"max" json:"max"`
+	Operands   int      `yaml:"operands" json:"operands"`
+
+	// Negative allows problems whose answer is negative.
+	Negative bool `yaml:"negative" json:"negative"`
+
+	Levels []GeneratorSpec `yaml:"levels" json:"levels"`
+}
+
+// DEFAULT_GENERATOR holds the value of every field that is not
+// set by a GeneratorSpec or its Difficulty.
+var DEFAULT_GENERATOR = GeneratorSpec{
+	Count:     10,
+	Operators: []string{"+"},
+	Min:       intPtr(1),
+	Max:       intPtr(10),
+	Operands:  2,
+}
+
+// difficulties maps the name of each difficulty level to its
+// preset. Generated problems are given the Difficulty of the
+// same name.
+var difficulties = map[string]GeneratorSpec{
+	"easy": {
+		Operators: []string{"+", "-"},
+		Min:       intPtr(1),
+		Max:       intPtr(10),
+		Operands:  2,
+	},
+	"medium": {
+		Operators: []string{"+", "-", "*"},
+		Min:       intPtr(1),
+		Max:       intPtr(20),
+		Operands:  2,
+	},
+	"hard": {
+		Operators: []string{"+", "-", "*", "/"},
+		Min:       intPtr(2),
+		Max:       intPtr(50),
+		Operands:  3,
+	},
+}
+
+// operators maps each supported operator to how it is applied.
+var operators = map[string]func(a, b *big.Rat) *big.Rat{
+	"+": func(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) },
+	"-": func(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) },
+	"*": func(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) },
+	"/": func(a, b *big.Rat) *big.Rat { return new(big.Rat).Quo(a, b) },
+}
+
+// maxAttempts is how many times a problem is regenerated when
+// it cannot be built within the level, e.g. because its answer
+// is negative and that is not allowed.
+const maxAttempts = 1000
+
+// LoadGenerator is the Loader for generator specs, which are
+// written in YAML (or JSON). See GeneratorSpec.
+func LoadGenerator(name string, r io.Reader) ([]Problem, error) {
+
+	spec, err := ReadGeneratorSpec(r)
+	if err != nil {
+		return nil, ParseErrors{{name, 0, err}}
+	}
+
+	problems, err := spec.Generate()
+	if err != nil {
+		return nil, ParseErrors{{name, 0, err}}
+	}
+
+	return problems, nil
+}
+
+// ReadGeneratorSpec decodes a GeneratorSpec written in YAML (or
+// JSON). An empty spec generates DEFAULT_GENERATOR.
+func ReadGeneratorSpec(r io.Reader) (GeneratorSpec, error) {
+
+	var spec GeneratorSpec
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && err != io.EOF {
+		return GeneratorSpec{}, err
+	}
+
+	return spec, nil
+}
+
+// Generate returns the problems described by the spec. Answers
+// are computed exactly, following the usual precedence of the
+// operators, and are always whole numbers.
+func (spec GeneratorSpec) Generate() ([]Problem, error) {
+
+	seed := spec.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	levels := spec.Levels
+	if len(levels) == 0 {
+		levels = []GeneratorSpec{{}}
+	}
+
+	var problems []Problem
+	for i, level := range levels {
+
+		level = level.resolve(spec)
+		if err := level.validate(); err != nil {
+			if len(spec.Levels) > 0 {
+				err = fmt.Errorf("level %d: %v", i+1, err)
+			}
+			return nil, err
+		}
+
+		for n := 0; n < level.Count; n++ {
+			p, err := level.generate(rng)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p)
+		}
+	}
+
+	return problems, nil
+}
+
+// resolve fills in the fields the level does not set from its
+// parent spec, then its Difficulty and then DEFAULT_GENERATOR.
+func (level GeneratorSpec) resolve(parent GeneratorSpec) GeneratorSpec {
+
+	if level.Difficulty == "" {
+		level.Difficulty = parent.Difficulty
+	}
+	preset := difficulties[strings.ToLower(level.Difficulty)]
+
+	for _, from := range []GeneratorSpec{parent, preset, DEFAULT_GENERATOR} {
+		if level.Count == 0 {
+			level.Count = from.Count
+		}
+		if len(level.Operators) == 0 {
+			level.Operators = from.Operators
+		}
+		if level.Min == nil {
+			level.Min = from.Min
+		}
+		if level.Max == nil {
+			level.Max = from.Max
+		}
+		if level.Operands == 0 {
+			level.Operands = from.Operands
+		}
+	}
+	level.Negative = level.Negative || parent.Negative
+
+	return level
+}
+
+// validate returns an error if the resolved level cannot be
+// generated.
+func (level GeneratorSpec) validate() error {
+
+	if level.Difficulty != "" {
+		if _, ok := difficulties[strings.ToLower(level.Difficulty)]; !ok {
+			var names []string
+			for name := range difficulties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf(
+				"unknown difficulty %q (expected one of %s)",
+				level.Difficulty, strings.Join(names, ", "),
+			)
+		}
+	}
+	for _, op := range level.Operators {
+		if _, ok := operators[op]; !ok {
+			return fmt.Errorf("unsupported operator %q (expected +, -, * or /)", op)
+		}
+	}
+	if level.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+	if *level.Min > *level.Max {
+		return fmt.Errorf("min %d is greater than max %d", *level.Min, *level.Max)
+	}
+	if level.Operands < 2 {
+		return errors.New("at least 2 operands are required")
+	}
+	for _, op := range level.Operators {
+		if op == "/" && *level.Min == 0 && *level.Max == 0 {
+			return errors.New("division requires a nonzero operand between min and max")
+		}
+	}
+
+	return nil
+}
+
+// generate returns a random problem of the level. Divisions
+// are built backwards so that they always come out whole: each
+// divisor is picked first, and the first operand of the product
+// or quotient it divides is then picked as a multiple of every
+// divisor in it, between the level's Min and Max.
+func (level GeneratorSpec) generate(rng *rand.Rand) (Problem, error) {
+
+attempts:
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+
+		operands := make([]int64, level.Operands)
+		for i := range operands {
+			operands[i] = level.operand(rng)
+		}
+		ops := make([]string, level.Operands-1)
+		for i := range ops {
+			ops[i] = level.Operators[rng.Intn(len(level.Operators))]
+		}
+
+		// start is the index of the first operand of the current
+		// product or quotient, and divisor the product of its
+		// divisors, which + and - begin anew.
+		start, divisor := 0, int64(1)
+		for i, op := range ops {
+			switch op {
+			case "+", "-":
+				start, divisor = i+1, 1
+			case "/":
+				for operands[i+1] == 0 {
+					operands[i+1] = level.operand(rng)
+				}
+				divisor *= abs(operands[i+1])
+				multiple, ok := level.multiple(rng, divisor)
+				if !ok {
+					continue attempts
+				}
+				operands[start] = multiple
+			}
+		}
+
+		answer, ok := evaluate(operands, ops)
+		if !ok || !answer.IsInt() || (!level.Negative && answer.Sign() < 0) {
+			continue
+		}
+
+		var question strings.Builder
+		for i, operand := range operands {
+			if i > 0 {
+				question.WriteString(ops[i-1])
+			}
+			fmt.Fprint(&question, operand)
+		}
+
+		return Problem{
+			Question:   question.String(),
+			Answer:     answer.Num().String(),
+			Difficulty: difficultyNames[strings.ToLower(level.Difficulty)],
+		}, nil
+	}
+
+	requirement := "whole"
+	if !level.Negative {
+		requirement = "whole, non-negative"
+	}
+
+	return Problem{}, fmt.Errorf(
+		"could not generate a %s problem with operators %s between %d and %d",
+		requirement, strings.Join(level.Operators, " "), *level.Min, *level.Max,
+	)
+}
+
+// operand returns a random operand between the level's Min and
+// Max.
+func (level GeneratorSpec) operand(rng *rand.Rand) int64 {
+	return int64(*level.Min + rng.Intn(*level.Max-*level.Min+1))
+}
+
+// multiple returns a random multiple of the positive divisor
+// between the level's Min and Max, or false if there is none.
+func (level GeneratorSpec) multiple(rng *rand.Rand, divisor int64) (int64, bool) {
+
+	// The multiples are divisor*k for k from lo to hi.
+	lo, hi := int64(*level.Min)/divisor, int64(*level.Max)/divisor
+	if lo*divisor < int64(*level.Min) {
+		lo++
+	}
+	if hi*divisor > int64(*level.Max) {
+		hi--
+	}
+	if lo > hi {
+		return 0, false
+	}
+
+	return divisor * (lo + rng.Int63n(hi-lo+1)), true
+}
+
+func abs(n int64) int64 {
+
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// evaluate computes the value of the operands joined by the
+// operators, applying * and / before + and -. It returns false
+// on division by zero.
+func evaluate(operands []int64, ops []string) (*big.Rat, bool) {
+
+	// Products and quotients are collapsed into terms, which are
+	// then summed.
+	terms := []*big.Rat{new(big.Rat).SetInt64(operands[0])}
+	signs := []string{"+"}
+	for i, op := range ops {
+		operand := new(big.Rat).SetInt64(operands[i+1])
+		switch op {
+		case "*", "/":
+			if op == "/" && operand.Sign() == 0 {
+				return nil, false
+			}
+			last := len(terms) - 1
+			terms[last] = operators[op](terms[last], operand)
+		default:
+			terms = append(terms, operand)
+			signs = append(signs, op)
+		}
+	}
+
+	result := new(big.Rat)
+	for i, term := range terms {
+		result = operators[signs[i]](result, term)
+	}
+
+	return result, true
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,190 @@
+package quiz
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// parseArithmetic splits a generated question into its operands
+// and operators, where an operand may be negative, e.g. "5--3".
+func parseArithmetic(t *testing.T, question string) ([]int64, []string) {
+
+	t.Helper()
+	var operands []int64
+	var ops []string
+	for rest := question; rest != ""; {
+		if len(operands) > len(ops) {
+			ops = append(ops, rest[:1])
+			rest = rest[1:]
+			continue
+		}
+		end := 1
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		n, err := strconv.ParseInt(rest[:end], 10, 64)
+		if err != nil {
+			t.Fatalf("%s: invalid operand %q", question, rest[:end])
+		}
+		operands = append(operands, n)
+		rest = rest[end:]
+	}
+
+	return operands, ops
+}
+
+func TestGenerateDivisions(t *testing.T) {
+
+	specs := []GeneratorSpec{
+		{Difficulty: "hard"},
+		{Difficulty: "hard", Operators: []string{"/"}},
+		{Operators: []string{"*", "/"}, Min: intPtr(1), Max: intPtr(10), Operands: 4},
+		{Operators: []string{"/", "+"}, Min: intPtr(0), Max: intPtr(12), Operands: 3},
+		{Operators: []string{"/", "-"}, Min: intPtr(-10), Max: intPtr(10), Operands: 3, Negative: true},
+		{Operators: []string{"/"}, Min: intPtr(5), Max: intPtr(6), Operands: 2},
+	}
+
+	for _, spec := range specs {
+		for seed := int64(1); seed <= 20; seed++ {
+			spec.Seed, spec.Count = seed, 20
+			problems, err := spec.Generate()
+			if err != nil {
+				t.Fatalf("%+v: %v", spec, err)
+			}
+			level := spec.resolve(GeneratorSpec{})
+
+			for _, p := range problems {
+				operands, ops := parseArithmetic(t, p.Question)
+				for _, operand := range operands {
+					if operand < int64(*level.Min) || operand > int64(*level.Max) {
+						t.Errorf("%s: operand %d is not between %d and %d",
+							p.Question, operand, *level.Min, *level.Max)
+					}
+				}
+
+				// Each product or quotient comes out whole as it is
+				// worked out from left to right.
+				var sum, term int64 = 0, operands[0]
+				sign := int64(1)
+				for i, op := range ops {
+					operand := operands[i+1]
+					switch op {
+					case "*":
+						term *= operand
+					case "/":
+						if operand == 0 || term%operand != 0 {
+							t.Errorf("%s: %d is not divisible by %d", p.Question, term, operand)
+							continue
+						}
+						term /= operand
+					default:
+						sum += sign * term
+						term, sign = operand, 1
+						if op == "-" {
+							sign = -1
+						}
+					}
+				}
+				sum += sign * term
+
+				answer, err := strconv.ParseInt(p.Answer, 10, 64)
+				if err != nil || answer != sum {
+					t.Errorf("%s: got the answer %q, want %d", p.Question, p.Answer, sum)
+				}
+				if !level.Negative && answer < 0 {
+					t.Errorf("%s: got the negative answer %d", p.Question, answer)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+
+	spec := GeneratorSpec{Seed: 42, Difficulty: "hard", Count: 10}
+
+	first, err := spec.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := spec.Generate()
+
+	for i := range first {
+		if first[i].Question != second[i].Question || first[i].Difficulty != DifficultyHard {
+			t.Fatalf("problem %d: got %+v and %+v from the same seed", i+1, first[i], second[i])
+		}
+	}
+}
+
+func TestGenerateLevels(t *testing.T) {
+
+	spec := GeneratorSpec{
+		Seed:   1,
+		Count:  3,
+		Levels: []GeneratorSpec{{Difficulty: "easy"}, {Difficulty: "medium", Count: 2}},
+	}
+
+	problems, err := spec.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var difficulties []int
+	for _, p := range problems {
+		difficulties = append(difficulties, p.Difficulty)
+	}
+	want := []int{DifficultyEasy, DifficultyEasy, DifficultyEasy, DifficultyMedium, DifficultyMedium}
+	if !equalInts(difficulties, want) {
+		t.Errorf("got the difficulties %v, want %v", difficulties, want)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+
+	tests := []struct {
+		name string
+		spec GeneratorSpec
+		err  string
+	}{
+		{"min greater than max", GeneratorSpec{Min: intPtr(5), Max: intPtr(4)}, "min 5 is greater than max 4"},
+		{
+			"division by zero only",
+			GeneratorSpec{Operators: []string{"+", "/"}, Min: intPtr(0), Max: intPtr(0)},
+			"division requires a nonzero operand",
+		},
+		{"unknown operator", GeneratorSpec{Operators: []string{"^"}}, `unsupported operator "^"`},
+		{"unknown difficulty", GeneratorSpec{Difficulty: "extreme"}, `unknown difficulty "extreme"`},
+		{"one operand", GeneratorSpec{Operands: 1}, "at least 2 operands"},
+		{"negative count", GeneratorSpec{Count: -1}, "count must not be negative"},
+		{
+			"within a level",
+			GeneratorSpec{Levels: []GeneratorSpec{{}, {Min: intPtr(3), Max: intPtr(1)}}},
+			"level 2: min 3 is greater than max 1",
+		},
+		{
+			"no whole quotient in range",
+			GeneratorSpec{Operators: []string{"/"}, Min: intPtr(3), Max: intPtr(4), Operands: 3},
+			"could not generate a whole, non-negative problem",
+		},
+		{
+			"no non-negative answer",
+			GeneratorSpec{Operators: []string{"+"}, Min: intPtr(-5), Max: intPtr(-1)},
+			"could not generate",
+		},
+	}
+
+	for _, test := range tests {
+		test.spec.Seed = 1
+		_, err := test.spec.Generate()
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want one containing %q", test.name, err, test.err)
+		}
+	}
+
+	// Zero is allowed as long as a nonzero divisor can be picked.
+	spec := GeneratorSpec{Seed: 1, Operators: []string{"/"}, Min: intPtr(0), Max: intPtr(1)}
+	if _, err := spec.Generate(); err != nil {
+		t.Errorf("division between 0 and 1: %v", err)
+	}
+}
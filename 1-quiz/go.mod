@@ -1,3 +1,13 @@
 module quiz
 
 go 1.18
+
+require (
+	github.com/gorilla/websocket v1.5.0
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/term v0.5.0
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.5.0 // indirect
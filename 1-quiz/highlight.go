@@ -0,0 +1,254 @@
+package quiz
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// syntax describes enough of a programming language to highlight
+// its keywords, comments, strings and numbers.
+type syntax struct {
+	keywords     []string
+	lineComment  string
+	blockComment [2]string
+	quotes       string
+}
+
+// syntaxes maps each language name a code snippet may be given,
+// e.g. in the info string of a fenced code block, to its syntax.
+var syntaxes = map[string]*syntax{}
+
+func init() {
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"break", "case", "chan", "const", "continue", "default", "defer", "else",
+			"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+			"map", "package", "range", "return", "select", "struct", "switch", "type",
+			"var", "nil", "true", "false", "iota",
+		},
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}, "go", "golang")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"and", "as", "assert", "async", "await", "break", "class", "continue",
+			"def", "del", "elif", "else", "except", "finally", "for", "from", "global",
+			"if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass",
+			"raise", "return", "try", "while", "with", "yield", "None", "True", "False",
+		},
+		lineComment: "#",
+		quotes:      `"'`,
+	}, "python", "py")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"async", "await", "break", "case", "catch", "class", "const", "continue",
+			"default", "delete", "do", "else", "export", "extends", "finally", "for",
+			"function", "if", "import", "in", "instanceof", "let", "new", "return",
+			"switch", "this", "throw", "try", "typeof", "var", "void", "while", "yield",
+			"null", "undefined", "true", "false", "interface", "type",
+		},
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}, "javascript", "js", "typescript", "ts")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"break", "case", "char", "const", "continue", "default", "do", "double",
+			"else", "enum", "extern", "float", "for", "goto", "if", "int", "long",
+			"return", "short", "signed", "sizeof", "static", "struct", "switch",
+			"typedef", "union", "unsigned", "void", "volatile", "while",
+			"bool", "class", "delete", "new", "namespace", "private", "protected",
+			"public", "template", "this", "throw", "try", "catch", "using", "virtual",
+		},
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}, "c", "h", "cpp", "c++", "cc")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"abstract", "boolean", "break", "byte", "case", "catch", "char", "class",
+			"continue", "default", "do", "double", "else", "enum", "extends", "final",
+			"finally", "float", "for", "if", "implements", "import", "instanceof", "int",
+			"interface", "long", "new", "package", "private", "protected", "public",
+			"return", "short", "static", "super", "switch", "this", "throw", "throws",
+			"try", "void", "while", "null", "true", "false",
+		},
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}, "java")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"as", "break", "const", "continue", "crate", "else", "enum", "fn", "for",
+			"if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub",
+			"ref", "return", "self", "Self", "static", "struct", "trait", "type",
+			"use", "where", "while", "true", "false",
+		},
+		lineComment:  "//",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"`,
+	}, "rust", "rs")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done",
+			"case", "esac", "in", "function", "return", "local", "export", "echo",
+		},
+		lineComment: "#",
+		quotes:      `"'`,
+	}, "sh", "bash", "shell", "zsh")
+
+	registerSyntax(&syntax{
+		keywords: []string{
+			"select", "from", "where", "and", "or", "not", "insert", "into", "values",
+			"update", "set", "delete", "create", "table", "drop", "alter", "join",
+			"left", "right", "inner", "outer", "on", "group", "by", "order", "having",
+			"limit", "as", "distinct", "null", "is", "in", "like", "between", "union",
+		},
+		lineComment:  "--",
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `'"`,
+	}, "sql")
+}
+
+// registerSyntax makes a syntax available for highlighting code
+// snippets in the specified languages.
+func registerSyntax(s *syntax, languages ...string) {
+
+	for _, language := range languages {
+		syntaxes[strings.ToLower(language)] = s
+	}
+}
+
+// ANSI colors of highlighted code.
+const (
+	colorKeyword = "\x1b[1;35m"
+	colorString  = "\x1b[32m"
+	colorComment = "\x1b[90m"
+	colorNumber  = "\x1b[33m"
+)
+
+// Highlight returns a code snippet with its keywords, comments,
+// strings and numbers colored by ANSI escape sequences. Code in
+// a language without a registered syntax is returned as it is.
+func Highlight(code string, language string) string {
+
+	s, ok := syntaxes[strings.ToLower(language)]
+	if !ok {
+		return code
+	}
+
+	keywords := map[string]bool{}
+	for _, k := range s.keywords {
+		keywords[k] = true
+	}
+	// SQL keywords are written in either case.
+	fold := strings.EqualFold(language, "sql")
+
+	var b strings.Builder
+	color := func(c string, text string) {
+		// Colors are reset at the end of each line, so that a
+		// multi-line comment or string does not bleed into the
+		// margin.
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if line != "" {
+				b.WriteString(c + line + colorDefault)
+			}
+		}
+	}
+
+	rest := code
+	for rest != "" {
+
+		switch {
+
+		case s.lineComment != "" && strings.HasPrefix(rest, s.lineComment):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			color(colorComment, rest[:end])
+			rest = rest[end:]
+
+		case s.blockComment[0] != "" && strings.HasPrefix(rest, s.blockComment[0]):
+			end := strings.Index(rest[len(s.blockComment[0]):], s.blockComment[1])
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += len(s.blockComment[0]) + len(s.blockComment[1])
+			}
+			color(colorComment, rest[:end])
+			rest = rest[end:]
+
+		case strings.IndexByte(s.quotes, rest[0]) >= 0:
+			end := stringEnd(rest)
+			color(colorString, rest[:end])
+			rest = rest[end:]
+
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			if !isWordRune(r) {
+				b.WriteString(rest[:size])
+				rest = rest[size:]
+				continue
+			}
+
+			end := strings.IndexFunc(rest, func(r rune) bool { return !isWordRune(r) })
+			if end < 0 {
+				end = len(rest)
+			}
+			word := rest[:end]
+			switch {
+			case unicode.IsDigit(r):
+				color(colorNumber, word)
+			case keywords[word] || fold && keywords[strings.ToLower(word)]:
+				color(colorKeyword, word)
+			default:
+				b.WriteString(word)
+			}
+			rest = rest[end:]
+
+		}
+	}
+
+	return b.String()
+}
+
+// stringEnd returns the length of the string literal at the start
+// of the code, up to and including its closing quote. A quote
+// escaped by a backslash does not close the string, and only a
+// backquote may span lines.
+func stringEnd(code string) int {
+
+	quote := code[0]
+	for i := 1; i < len(code); i++ {
+		switch {
+		case code[i] == '\\' && quote != '`':
+			i++
+		case code[i] == quote:
+			return i + 1
+		case code[i] == '\n' && quote != '`':
+			return i
+		}
+	}
+
+	return len(code)
+}
+
+// isWordRune reports whether r may be part of an identifier,
+// keyword or number.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,260 @@
+package quiz
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// attemptsBucket is the BoltDB bucket holding a nested bucket
+// of Attempts for each user.
+var attemptsBucket = []byte("attempts")
+
+// reviewsBucket is the BoltDB bucket holding a nested bucket of
+// Reviews, keyed by question, for each user.
+var reviewsBucket = []byte("reviews")
+
+// AnswerRecord is an Answer as it is recorded in the History.
+type AnswerRecord struct {
+	Question string        `json:"question"`
+	Category string        `json:"category,omitempty"`
+	Expected string        `json:"expected"`
+	Response string        `json:"response"`
+	Correct  bool          `json:"correct"`
+	Points   float64       `json:"points"`
+	TimedOut bool          `json:"timed_out,omitempty"`
+	Latency  time.Duration `json:"latency"`
+	Flags    []Flag        `json:"flags,omitempty"`
+
+	// Hash links the answer into the keyed hash chain of its
+	// Attempt. See Quiz.Key.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Attempt is a completed quiz run by a user, as it is recorded
+// in the History.
+type Attempt struct {
+	User     string         `json:"user"`
+	Source   string         `json:"source"`
+	Started  time.Time      `json:"started"`
+	Seed     int64          `json:"seed"`
+	Score    float64        `json:"score"`
+	MaxScore float64        `json:"max_score"`
+	Correct  int            `json:"correct"`
+	Total    int            `json:"total"`
+	Answers  []AnswerRecord `json:"answers"`
+
+	// Chain is the hash of the last answer, at the head of the
+	// hash chain of Answers, or empty if the answers were not
+	// chained. See Attempt.Verify.
+	Chain string `json:"chain,omitempty"`
+}
+
+// NewAttempt returns the Attempt recording a user's Result for
+// a Quiz loaded from the specified source. The answers keep the
+// hash chain they were linked into as they were submitted.
+func NewAttempt(user string, source string, result Result) Attempt {
+
+	attempt := Attempt{
+		User:     user,
+		Source:   source,
+		Started:  result.Started,
+		Seed:     result.Seed,
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Correct:  result.Correct,
+		Total:    result.Total,
+	}
+
+	for _, a := range result.Answers {
+		attempt.Answers = append(attempt.Answers, newAnswerRecord(a))
+		attempt.Chain = a.Hash
+	}
+
+	return attempt
+}
+
+// newAnswerRecord returns the AnswerRecord of an Answer.
+func newAnswerRecord(a Answer) AnswerRecord {
+
+	return AnswerRecord{
+		Question: a.Problem.Question,
+		Category: a.Problem.Category,
+		Expected: a.Problem.Answer,
+		Response: a.Response,
+		Correct:  a.Correct,
+		Points:   a.Points,
+		TimedOut: a.TimedOut,
+		Latency:  a.Latency,
+		Flags:    a.Flags,
+		Hash:     a.Hash,
+	}
+}
+
+// History is a store of Attempts backed by a BoltDB database.
+type History struct {
+	db *bolt.DB
+}
+
+// OpenHistory opens the History at the specified filepath,
+// creating it if it does not exist.
+func OpenHistory(path string) (*History, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return &History{db}, nil
+}
+
+// Close closes the History's database.
+func (h *History) Close() error {
+	return h.db.Close()
+}
+
+// Save records an Attempt under its user.
+func (h *History) Save(attempt Attempt) error {
+
+	value, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+
+	return h.db.Update(func(tx *bolt.Tx) error {
+
+		attempts, err := tx.CreateBucketIfNotExists(attemptsBucket)
+		if err != nil {
+			return err
+		}
+		b, err := attempts.CreateBucketIfNotExists([]byte(attempt.User))
+		if err != nil {
+			return err
+		}
+
+		// Keys are sequential so that Attempts are kept in the
+		// order they were saved.
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+
+		return b.Put(key, value)
+	})
+}
+
+// Attempts returns every Attempt by the specified user in the
+// order they were saved.
+func (h *History) Attempts(user string) ([]Attempt, error) {
+
+	var result []Attempt
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+
+		attempts := tx.Bucket(attemptsBucket)
+		if attempts == nil {
+			return nil
+		}
+		b := attempts.Bucket([]byte(user))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			var attempt Attempt
+			if err := json.Unmarshal(v, &attempt); err != nil {
+				return err
+			}
+			result = append(result, attempt)
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// Users returns the name of every user with a saved Attempt.
+func (h *History) Users() ([]string, error) {
+
+	var users []string
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+
+		attempts := tx.Bucket(attemptsBucket)
+		if attempts == nil {
+			return nil
+		}
+
+		return attempts.ForEach(func(k, v []byte) error {
+			users = append(users, string(k))
+			return nil
+		})
+	})
+	sort.Strings(users)
+
+	return users, err
+}
+
+// Reviews returns the spaced-repetition schedule of every
+// question reviewed by the specified user, keyed by question.
+func (h *History) Reviews(user string) (map[string]Review, error) {
+
+	reviews := map[string]Review{}
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+
+		users := tx.Bucket(reviewsBucket)
+		if users == nil {
+			return nil
+		}
+		b := users.Bucket([]byte(user))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			var review Review
+			if err := json.Unmarshal(v, &review); err != nil {
+				return err
+			}
+			reviews[string(k)] = review
+			return nil
+		})
+	})
+
+	return reviews, err
+}
+
+// SaveReviews records the spaced-repetition schedule of each of
+// the specified questions for a user.
+func (h *History) SaveReviews(user string, reviews map[string]Review) error {
+
+	return h.db.Update(func(tx *bolt.Tx) error {
+
+		users, err := tx.CreateBucketIfNotExists(reviewsBucket)
+		if err != nil {
+			return err
+		}
+		b, err := users.CreateBucketIfNotExists([]byte(user))
+		if err != nil {
+			return err
+		}
+
+		for question, review := range reviews {
+			value, err := json.Marshal(review)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(question), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,144 @@
+package quiz
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// response is a line of user input and when it was read.
+type response struct {
+	text string
+	err  error
+	read time.Time
+}
+
+// Input reads lines of user input in a goroutine of its own, so
+// that waiting for a line can be abandoned when a timer fires or
+// a context is cancelled.
+type Input struct {
+	r     io.Reader
+	clock Clock
+	lines chan response
+	done  chan struct{}
+	once  sync.Once
+
+	// err is the error that ended the input, returned for every
+	// line after it.
+	err error
+}
+
+// NewInput starts reading lines from r. Close the Input to stop
+// reading once it is no longer needed.
+func NewInput(r io.Reader) *Input {
+	return NewClockInput(r, RealClock{})
+}
+
+// NewClockInput is NewInput with the clock that each line is
+// timed by as it is read, which should be the clock of the
+// Session it answers.
+func NewClockInput(r io.Reader, clock Clock) *Input {
+
+	input := &Input{
+		r:     r,
+		clock: clock,
+		lines: make(chan response),
+		done:  make(chan struct{}),
+	}
+	go input.read(bufio.NewReader(r))
+
+	return input
+}
+
+// read sends every line to be received by ReadLine, until the
+// input ends or the Input is closed.
+func (in *Input) read(reader *bufio.Reader) {
+
+	for {
+		text, err := reader.ReadString('\n')
+		select {
+		case in.lines <- response{text, err, in.clock.Now()}:
+		case <-in.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ReadLine returns the next line of input, without its line
+// ending. It returns the context's error if it is cancelled
+// first, in which case the line is left to the next ReadLine.
+func (in *Input) ReadLine(ctx context.Context) (string, error) {
+
+	if in.err != nil {
+		return "", in.err
+	}
+
+	select {
+	case r := <-in.lines:
+		return in.accept(r)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// next returns the channel on which the next line is received,
+// which is nil once the input has ended. A line received from it
+// must be passed to accept.
+func (in *Input) next() <-chan response {
+
+	if in.err != nil {
+		return nil
+	}
+
+	return in.lines
+}
+
+// accept returns a line received from next without its line
+// ending, keeping any error to return for every later line. A
+// last line without a line ending, e.g. from "printf 10", is
+// returned before the end of the input is reported.
+func (in *Input) accept(r response) (string, error) {
+
+	if r.err != nil {
+		in.err = r.err
+		if r.err != io.EOF || r.text == "" {
+			return "", r.err
+		}
+	}
+
+	return strings.TrimRight(r.text, "\r\n"), nil
+}
+
+// WaitForEnter waits for the user to press enter. It returns
+// ErrAborted if they enter text instead.
+func (in *Input) WaitForEnter(ctx context.Context) error {
+
+	line, err := in.ReadLine(ctx)
+	if err != nil {
+		return err
+	}
+	if line != "" {
+		return ErrAborted
+	}
+
+	return nil
+}
+
+// Close stops reading input. A read that is already under way is
+// interrupted if the reader supports deadlines, e.g. a pipe, and
+// otherwise the goroutine exits as soon as the read returns.
+func (in *Input) Close() {
+
+	in.once.Do(func() {
+		close(in.done)
+		if d, ok := in.r.(interface{ SetReadDeadline(time.Time) error }); ok {
+			d.SetReadDeadline(time.Now())
+		}
+	})
+}
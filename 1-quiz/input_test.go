@@ -0,0 +1,40 @@
+package quiz
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInputReadLine(t *testing.T) {
+
+	tests := []struct {
+		input string
+		lines []string
+	}{
+		{"", nil},
+		{"a\n", []string{"a"}},
+		{"a\r\nb\n", []string{"a", "b"}},
+		{"a\nb", []string{"a", "b"}},
+		{"\n", []string{""}},
+		{"a\n\n", []string{"a", ""}},
+	}
+
+	for _, test := range tests {
+		input := NewInput(strings.NewReader(test.input))
+		for _, want := range test.lines {
+			if line, err := input.ReadLine(context.Background()); line != want || err != nil {
+				t.Errorf("%q: got %q, %v, want %q", test.input, line, err, want)
+			}
+		}
+
+		// The end of the input is reported for every line after it.
+		for i := 0; i < 2; i++ {
+			if line, err := input.ReadLine(context.Background()); line != "" || err != io.EOF {
+				t.Errorf("%q: got %q, %v at the end, want %v", test.input, line, err, io.EOF)
+			}
+		}
+		input.Close()
+	}
+}
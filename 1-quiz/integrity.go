@@ -0,0 +1,309 @@
+package quiz
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Flag marks an Answer whose integrity is in doubt.
+type Flag string
+
+const (
+	// FlagFast marks a response given faster than the question
+	// could plausibly have been read. See Proctor.
+	FlagFast Flag = "fast"
+
+	// FlagPasted marks a response that was pasted in, or entered
+	// before the question was shown.
+	FlagPasted Flag = "pasted"
+)
+
+// Proctor sets the checks made on each response when a quiz is
+// taken under exam conditions.
+type Proctor struct {
+	// MinLatency is the least time in which any question can be
+	// answered honestly.
+	MinLatency time.Duration
+
+	// PerCharacter is the further time taken to read each
+	// character of the question and its options.
+	PerCharacter time.Duration
+}
+
+// NewProctor returns a Proctor allowing at least half a second
+// per question and 15ms per character.
+func NewProctor() *Proctor {
+	return &Proctor{MinLatency: 500 * time.Millisecond, PerCharacter: 15 * time.Millisecond}
+}
+
+// TooFast reports whether a response was given in less time
+// than it takes to read the question. Unanswered questions are
+// never too fast.
+func (p *Proctor) TooFast(prompt Prompt, answer Answer) bool {
+
+	if answer.TimedOut || strings.TrimSpace(answer.Response) == "" {
+		return false
+	}
+
+	characters := len([]rune(prompt.Problem.Question))
+	for _, option := range prompt.Options {
+		characters += len([]rune(option))
+	}
+
+	return answer.Latency < p.MinLatency+time.Duration(characters)*p.PerCharacter
+}
+
+// Escape sequences that make a terminal mark pasted text. The
+// markers pass through the terminal's line editing, so they are
+// seen within the line that was pasted.
+const (
+	BRACKETED_PASTE_ON  = "\x1b[?2004h"
+	BRACKETED_PASTE_OFF = "\x1b[?2004l"
+	pasteStart          = "\x1b[200~"
+	pasteEnd            = "\x1b[201~"
+)
+
+// stripPaste removes the markers of bracketed paste from a line
+// of input, reporting whether there were any.
+func stripPaste(text string) (string, bool) {
+
+	if !strings.Contains(text, pasteStart) && !strings.Contains(text, pasteEnd) {
+		return text, false
+	}
+	text = strings.ReplaceAll(text, pasteStart, "")
+	text = strings.ReplaceAll(text, pasteEnd, "")
+
+	return text, true
+}
+
+// Flagged returns the number of Answers with a Flag.
+func (r Result) Flagged() int {
+
+	n := 0
+	for _, a := range r.Answers {
+		if len(a.Flags) > 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+// chainAnswer links the last of the Answers into the hash chain
+// of those before it, keyed with the key, giving it the
+// HMAC-SHA256 of its own record and of the hash of the answer
+// before it. Editing, inserting, removing or reordering any
+// answer breaks the chain from that answer on, and the chain
+// cannot be rebuilt without the key.
+func chainAnswer(answers []Answer, key []byte) {
+
+	prev := ""
+	if n := len(answers); n > 1 {
+		prev = answers[n-2].Hash
+	}
+	last := &answers[len(answers)-1]
+	last.Hash = answerHash(key, prev, newAnswerRecord(*last))
+}
+
+// answerHash returns the keyed hash of an AnswerRecord following
+// the answer with the specified hash.
+func answerHash(key []byte, prev string, a AnswerRecord) string {
+
+	a.Hash = ""
+	content, err := json.Marshal(a)
+	if err != nil {
+		panic(err)
+	}
+
+	return sign(append([]byte(prev), content...), key)
+}
+
+// Verify checks the hash chain of the Attempt's answers with the
+// key it was built with, and that its Score and Correct count
+// agree with them.
+func (a Attempt) Verify(key []byte) error {
+
+	prev := ""
+	for i, answer := range a.Answers {
+		if !hmac.Equal([]byte(answerHash(key, prev, answer)), []byte(answer.Hash)) {
+			return fmt.Errorf("answer %d breaks the hash chain", i+1)
+		}
+		prev = answer.Hash
+	}
+	if prev != a.Chain {
+		return errors.New("the head of the hash chain does not match the answers")
+	}
+
+	var score float64
+	var correct int
+	for _, answer := range a.Answers {
+		score += answer.Points
+		if answer.Correct {
+			correct++
+		}
+	}
+	if score != a.Score || correct != a.Correct {
+		return errors.New("the score does not match the answers")
+	}
+
+	return nil
+}
+
+// ErrBadSignature is returned when a signed result was not
+// signed with the key, or was changed after it was signed.
+var ErrBadSignature = errors.New("signature does not match; the result was changed or signed with another key")
+
+// SignedAttempt is an Attempt along with an HMAC-SHA256
+// signature of it. The Attempt is kept exactly as it was signed,
+// as compact JSON, apart from any indentation.
+type SignedAttempt struct {
+	Attempt   json.RawMessage `json:"attempt"`
+	Signature string          `json:"signature"`
+}
+
+// LoadKey returns the signing key kept in hex in the file at the
+// specified path, creating a random key there if there is none
+// yet. Only the owner can read a created key.
+func LoadKey(path string) ([]byte, error) {
+
+	text, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(text)))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("%s: invalid key (expected hex)", path)
+	}
+
+	return key, nil
+}
+
+// SignAttempt returns the Attempt signed with the key.
+func SignAttempt(attempt Attempt, key []byte) (SignedAttempt, error) {
+
+	content, err := json.Marshal(attempt)
+	if err != nil {
+		return SignedAttempt{}, err
+	}
+
+	return SignedAttempt{content, sign(content, key)}, nil
+}
+
+// sign returns the hex HMAC-SHA256 of the content.
+func sign(content []byte, key []byte) string {
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Open checks the signature with the key and returns the
+// Attempt once its hash chain is also verified.
+func (s SignedAttempt) Open(key []byte) (Attempt, error) {
+
+	signature, err := hex.DecodeString(s.Signature)
+	if err != nil {
+		return Attempt{}, ErrBadSignature
+	}
+	// The Attempt was signed as compact JSON, but may since have
+	// been indented.
+	var content bytes.Buffer
+	if err := json.Compact(&content, s.Attempt); err != nil {
+		return Attempt{}, err
+	}
+	expected, _ := hex.DecodeString(sign(content.Bytes(), key))
+	if !hmac.Equal(signature, expected) {
+		return Attempt{}, ErrBadSignature
+	}
+
+	var attempt Attempt
+	if err := json.Unmarshal(s.Attempt, &attempt); err != nil {
+		return Attempt{}, err
+	}
+	if err := attempt.Verify(key); err != nil {
+		return Attempt{}, err
+	}
+
+	return attempt, nil
+}
+
+// WriteSignedReport writes the Attempt, signed with the key, as
+// indented JSON. See ReadSignedReport.
+func WriteSignedReport(w io.Writer, attempt Attempt, key []byte) error {
+
+	signed, err := SignAttempt(attempt, key)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(signed)
+}
+
+// ReadSignedReport reads a report written by WriteSignedReport
+// and returns its Attempt, or an error if the report was changed
+// or not signed with the key.
+func ReadSignedReport(r io.Reader, key []byte) (Attempt, error) {
+
+	var signed SignedAttempt
+	if err := json.NewDecoder(r).Decode(&signed); err != nil {
+		return Attempt{}, err
+	}
+
+	return signed.Open(key)
+}
+
+// clearScrollback clears the terminal along with its scrollback.
+const clearScrollback = "\x1b[H\x1b[2J\x1b[3J"
+
+// hidingView is a View that clears the terminal before each
+// question, so that questions already answered cannot be read
+// back.
+type hidingView struct {
+	View
+	out io.Writer
+}
+
+// HideAnswered returns a View that presents the Session in the
+// view but clears the terminal, including its scrollback, before
+// each question is asked.
+func HideAnswered(view View, out io.Writer) View {
+	return &hidingView{view, out}
+}
+
+func (v *hidingView) Ask(s *Session, prompt Prompt) {
+
+	io.WriteString(v.out, clearScrollback)
+	v.View.Ask(s, prompt)
+}
+
+func (v *hidingView) Refresh(s *Session) {
+
+	if refresher, ok := v.View.(Refresher); ok {
+		refresher.Refresh(s)
+	}
+}
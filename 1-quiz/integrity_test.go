@@ -0,0 +1,163 @@
+package quiz
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newTestAttempt answers a quiz keyed with the key, one answer at
+// a time, and returns its Attempt.
+func newTestAttempt(t *testing.T, key []byte) Attempt {
+
+	t.Helper()
+	q := newTestQuiz(t, "5+5,10\n1+1,2\n8+3,11\n")
+	q.Key = key
+	clock := newFakeClock()
+	s := q.Start(clock)
+
+	for _, response := range []string{"10", "3", "11"} {
+		if _, ok := s.Next(); !ok {
+			t.Fatal("the session finished early")
+		}
+		clock.Advance(2 * time.Second)
+		if _, err := s.Submit(response); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	return NewAttempt("alice", "test.csv", s.Result())
+}
+
+func TestAttemptVerify(t *testing.T) {
+
+	attempt := newTestAttempt(t, testKey)
+	if attempt.Chain == "" || attempt.Chain != attempt.Answers[2].Hash {
+		t.Fatalf("got the chain %q, want the hash of the last answer", attempt.Chain)
+	}
+	if err := attempt.Verify(testKey); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *Attempt)
+		key    []byte
+		err    string
+	}{
+		{
+			name: "changed answer",
+			change: func(a *Attempt) {
+				a.Answers[1].Response, a.Answers[1].Correct = "2", true
+				a.Answers[1].Points, a.Correct, a.Score = 1, 3, 3
+			},
+			err: "answer 2 breaks the hash chain",
+		},
+		{
+			name: "reordered answers",
+			change: func(a *Attempt) {
+				a.Answers[0], a.Answers[1] = a.Answers[1], a.Answers[0]
+			},
+			err: "answer 1 breaks the hash chain",
+		},
+		{
+			name:   "removed answer",
+			change: func(a *Attempt) { a.Answers = a.Answers[:2] },
+			err:    "head of the hash chain",
+		},
+		{
+			name: "rechained with another key",
+			change: func(a *Attempt) {
+				*a = newTestAttempt(t, []byte("another key"))
+			},
+			err: "answer 1 breaks the hash chain",
+		},
+		{
+			name:   "wrong key",
+			change: func(a *Attempt) {},
+			key:    []byte("another key"),
+			err:    "answer 1 breaks the hash chain",
+		},
+		{
+			name:   "changed score",
+			change: func(a *Attempt) { a.Score++ },
+			err:    "the score does not match",
+		},
+	}
+
+	for _, test := range tests {
+		changed := newTestAttempt(t, testKey)
+		test.change(&changed)
+		key := test.key
+		if key == nil {
+			key = testKey
+		}
+
+		err := changed.Verify(key)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want one containing %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestAttemptWithoutKey(t *testing.T) {
+
+	attempt := newTestAttempt(t, nil)
+
+	if attempt.Chain != "" || attempt.Answers[0].Hash != "" {
+		t.Errorf("got the chain %q without a key, want none", attempt.Chain)
+	}
+	if err := attempt.Verify(testKey); err == nil {
+		t.Error("Verify of unchained answers: got no error")
+	}
+}
+
+func TestSignedReport(t *testing.T) {
+
+	attempt := newTestAttempt(t, testKey)
+
+	var report bytes.Buffer
+	if err := WriteSignedReport(&report, attempt, testKey); err != nil {
+		t.Fatalf("WriteSignedReport: %v", err)
+	}
+
+	opened, err := ReadSignedReport(bytes.NewReader(report.Bytes()), testKey)
+	if err != nil {
+		t.Fatalf("ReadSignedReport: %v", err)
+	}
+	if opened.User != "alice" || opened.Chain != attempt.Chain || len(opened.Answers) != 3 {
+		t.Errorf("got %+v, want the attempt as it was signed", opened)
+	}
+
+	if _, err := ReadSignedReport(bytes.NewReader(report.Bytes()), []byte("another key")); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("wrong key: got error %v, want %v", err, ErrBadSignature)
+	}
+
+	changed := strings.Replace(report.String(), `"response": "3"`, `"response": "2"`, 1)
+	if changed == report.String() {
+		t.Fatal("the report has no response to change")
+	}
+	if _, err := ReadSignedReport(strings.NewReader(changed), testKey); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("changed answer: got error %v, want %v", err, ErrBadSignature)
+	}
+}
+
+func TestOpenVerifiesChain(t *testing.T) {
+
+	// An attempt whose answers were changed before it was signed
+	// is caught by its hash chain rather than its signature.
+	attempt := newTestAttempt(t, testKey)
+	attempt.Answers[0], attempt.Answers[1] = attempt.Answers[1], attempt.Answers[0]
+
+	signed, err := SignAttempt(attempt, testKey)
+	if err != nil {
+		t.Fatalf("SignAttempt: %v", err)
+	}
+	if _, err := signed.Open(testKey); err == nil || errors.Is(err, ErrBadSignature) {
+		t.Errorf("got error %v, want a broken hash chain", err)
+	}
+}
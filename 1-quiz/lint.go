@@ -0,0 +1,116 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Diagnostic is a problem found in a problem source by Lint.
+type Diagnostic struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (d Diagnostic) String() string {
+	return (&ParseError{d.File, d.Line, d.Err}).Error()
+}
+
+// LintFile checks the problem source at the specified path,
+// returning a Diagnostic for every malformed record along with
+// those found by Lint. If format is empty it is chosen by file
+// extension. An error is returned only if the source could not
+// be read at all.
+func LintFile(path string, format string) ([]Diagnostic, error) {
+
+	problems, err := LoadFile(path, format)
+
+	var diagnostics []Diagnostic
+	var parseErrs ParseErrors
+	if errors.As(err, &parseErrs) {
+		for _, e := range parseErrs {
+			diagnostics = append(diagnostics, Diagnostic{e.File, e.Line, e.Err})
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	diagnostics = append(diagnostics, Lint(path, problems)...)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+
+	return diagnostics, nil
+}
+
+// Lint checks Problems that were parsed from the named source
+// for mistakes that are not errors in any single record:
+//
+//   - questions asked more than once with the same answer;
+//   - questions asked more than once with different answers;
+//   - options repeated within a question.
+//
+// Questions are compared once they are stripped of formatting.
+func Lint(name string, problems []Problem) []Diagnostic {
+
+	var diagnostics []Diagnostic
+	report := func(p Problem, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{name, p.Line, fmt.Errorf(format, args...)})
+	}
+
+	first := map[string]Problem{}
+	for _, p := range problems {
+
+		question := normalize(p.Question)
+		if earlier, ok := first[question]; ok {
+			if sameAnswers(earlier, p) {
+				report(p, "duplicate question %q (first asked%s)", p.Question, onLine(earlier))
+			} else {
+				report(
+					p, "question %q expects %q, conflicting with %q%s",
+					p.Question, p.Answer, earlier.Answer, onLine(earlier),
+				)
+			}
+		} else {
+			first[question] = p
+		}
+
+		seen := map[string]bool{}
+		for _, option := range p.Options {
+			if seen[normalize(option)] {
+				report(p, "duplicate option %q", option)
+			}
+			seen[normalize(option)] = true
+		}
+	}
+
+	return diagnostics
+}
+
+// sameAnswers reports whether two Problems accept the same
+// answers, once they are stripped of formatting.
+func sameAnswers(a Problem, b Problem) bool {
+
+	normalized := func(p Problem) string {
+		var answers []string
+		for _, answer := range p.Accepted() {
+			answers = append(answers, normalize(answer))
+		}
+		sort.Strings(answers)
+		return strings.Join(answers, "\n")
+	}
+
+	return normalized(a) == normalized(b)
+}
+
+// onLine describes where a Problem was read from, if known.
+func onLine(p Problem) string {
+
+	if p.Line == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" on line %d", p.Line)
+}
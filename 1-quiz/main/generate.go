@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"quiz"
+	"strings"
+)
+
+var DEFAULT_OUTPUT = ""
+
+// runGenerate writes a generated set of arithmetic problems as
+// CSV, which can then be taken with -filepath. Flags override
+// the corresponding fields of the -spec.
+func runGenerate(args []string) {
+
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+
+	var specPath string
+	flags.StringVar(
+		&specPath,
+		"spec",
+		"",
+		"Filepath to a generator spec (YAML or JSON).",
+	)
+
+	var output string
+	flags.StringVar(
+		&output,
+		"output",
+		DEFAULT_OUTPUT,
+		"Filepath to write the problems to (empty for standard output).",
+	)
+
+	var seed int64
+	flags.Int64Var(
+		&seed,
+		"seed",
+		DEFAULT_SEED,
+		"Seed for generating, to reproduce a problem set (0 chooses one at random).",
+	)
+
+	var count int
+	flags.IntVar(
+		&count,
+		"count",
+		quiz.DEFAULT_GENERATOR.Count,
+		"Number of problems to generate.",
+	)
+
+	var difficulty string
+	flags.StringVar(
+		&difficulty,
+		"difficulty",
+		"",
+		"Difficulty preset (easy, medium, hard).",
+	)
+
+	var operators string
+	flags.StringVar(
+		&operators,
+		"operators",
+		strings.Join(quiz.DEFAULT_GENERATOR.Operators, ""),
+		"Operators to use, e.g. +-*/.",
+	)
+
+	var min int
+	flags.IntVar(
+		&min,
+		"min",
+		*quiz.DEFAULT_GENERATOR.Min,
+		"Smallest operand.",
+	)
+
+	var max int
+	flags.IntVar(
+		&max,
+		"max",
+		*quiz.DEFAULT_GENERATOR.Max,
+		"Largest operand.",
+	)
+
+	var operands int
+	flags.IntVar(
+		&operands,
+		"operands",
+		quiz.DEFAULT_GENERATOR.Operands,
+		"Number of operands in each problem.",
+	)
+
+	var negative bool
+	flags.BoolVar(
+		&negative,
+		"negative",
+		false,
+		"Allow problems with negative answers.",
+	)
+
+	flags.Parse(args)
+
+	var spec quiz.GeneratorSpec
+	if specPath != "" {
+		file, err := os.Open(specPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		spec, err = quiz.ReadGeneratorSpec(file)
+		file.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", specPath, err)
+		}
+	}
+
+	// Only the flags that were set override the spec, so that its
+	// fields are otherwise left to the difficulty preset.
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			spec.Seed = seed
+		case "count":
+			spec.Count = count
+		case "difficulty":
+			spec.Difficulty = difficulty
+		case "operators":
+			spec.Operators = strings.Split(operators, "")
+		case "min":
+			spec.Min = &min
+		case "max":
+			spec.Max = &max
+		case "operands":
+			spec.Operands = operands
+		case "negative":
+			spec.Negative = negative
+		}
+	})
+
+	problems, err := spec.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := os.Stdout
+	if output != "" {
+		out, err = os.Create(output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := quiz.WriteCSV(out, problems); err != nil {
+		log.Fatal(err)
+	}
+	if output != "" {
+		if err := out.Close(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d problems to %s.\n", len(problems), output)
+	}
+}
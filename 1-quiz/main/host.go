@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"quiz"
+	"strings"
+	"time"
+)
+
+var DEFAULT_QUESTION_TIME = 20
+
+// runHost executes a local server hosting a live quiz room that
+// players join with a code. The host drives the quiz from the
+// terminal, pressing enter to ask each question.
+func runHost(args []string) {
+
+	flags := flag.NewFlagSet("host", flag.ExitOnError)
+
+	var filepath string
+	flags.StringVar(
+		&filepath,
+		"filepath",
+		DEFAULT_FILEPATH,
+		"Filepath (global), http(s) URL or bundle (directory or .zip, optionally #bank) of quiz data.",
+	)
+
+	var format string
+	flags.StringVar(
+		&format,
+		"format",
+		"",
+		"Format of the quiz data ("+strings.Join(quiz.Formats(), ", ")+"). "+
+			"Defaults to the format of the file extension.",
+	)
+
+	var cacheDir string
+	flags.StringVar(
+		&cacheDir,
+		"cache",
+		DEFAULT_CACHE,
+		"Directory quiz data fetched from a URL is cached in.",
+	)
+
+	var match string
+	flags.StringVar(
+		&match,
+		"match",
+		quiz.DEFAULT_MATCH,
+		"Answer matcher for questions that do not specify one ("+
+			strings.Join(quiz.Matchers(), ", ")+"), e.g. fuzzy:2.",
+	)
+
+	var shuffleFlag bool
+	flags.BoolVar(
+		&shuffleFlag,
+		"shuffle",
+		DEFAULT_SHUFFLE,
+		"Shuffle the quiz data.",
+	)
+
+	var questionTime int
+	flags.IntVar(
+		&questionTime,
+		"question_time",
+		DEFAULT_QUESTION_TIME,
+		"Time to answer each question without its own time limit (in seconds).",
+	)
+
+	var addr string
+	flags.StringVar(
+		&addr,
+		"addr",
+		DEFAULT_ADDR,
+		"Address to serve the room on.",
+	)
+
+	flags.Parse(args)
+
+	problems := loadProblems(filepath, format, match, cacheDir)
+	if shuffleFlag {
+		problems = quiz.Shuffle(problems, rand.New(rand.NewSource(time.Now().UnixNano())))
+	}
+
+	room := quiz.NewRoom(quiz.NewRoomCode(), &quiz.Quiz{Problems: problems}, quiz.RealClock{})
+	room.QuestionTime = time.Duration(questionTime) * time.Second
+
+	server := quiz.NewRoomServer()
+	server.Add(room)
+	go func() {
+		log.Fatal(http.ListenAndServe(addr, server))
+	}()
+
+	fmt.Printf("Players can join on %s with the code %s.\n", addr, room.Code)
+	fmt.Println("Press enter to ask each question, or type q to end the quiz.")
+
+	events := room.Watch()
+	printed := make(chan struct{})
+	go func() {
+		for event := range events {
+			printRoomEvent(event)
+		}
+		close(printed)
+	}()
+
+	in := bufio.NewReader(os.Stdin)
+	for !room.Finished() {
+		line, err := in.ReadString('\n')
+		if err != nil || strings.TrimSpace(line) == "q" {
+			room.End()
+			break
+		}
+		room.Next()
+	}
+
+	// The events are closed once the room has finished, after the
+	// final leaderboard.
+	<-printed
+}
+
+// printRoomEvent shows the host what is happening in the room.
+func printRoomEvent(event quiz.RoomEvent) {
+
+	switch event.Type {
+
+	case "joined":
+		fmt.Printf("%s joined (%d players).\n", event.Player, len(event.Leaderboard))
+
+	case "left":
+		fmt.Printf("%s left (%d players).\n", event.Player, len(event.Leaderboard))
+
+	case "question":
+		q := event.Question
+		fmt.Printf("%d. %s? [%gs]\n", q.Number, q.Question, q.TimeLimit)
+		for _, option := range q.Options {
+			fmt.Printf("   %s) %s\n", option.Letter, option.Text)
+		}
+
+	case "answered":
+		fmt.Printf("%s answered.\n", event.Player)
+
+	case "reveal", "finished":
+		if event.Type == "reveal" {
+			fmt.Printf("The answer was %q.\n", event.Answer)
+		} else {
+			fmt.Println("The quiz has finished!")
+		}
+		for i, s := range event.Leaderboard {
+			fmt.Printf("  %d. %-20s %6g (%d correct)\n", i+1, s.Name, s.Score, s.Correct)
+		}
+
+	}
+}
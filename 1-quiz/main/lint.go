@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"quiz"
+	"strings"
+)
+
+// runLint checks each problem file named by the arguments and
+// prints a diagnostic for every mistake found, exiting with a
+// non-zero status if there were any.
+func runLint(args []string) {
+
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: quiz lint [flags] file...")
+		flags.PrintDefaults()
+	}
+
+	var format string
+	flags.StringVar(
+		&format,
+		"format",
+		"",
+		"Format of the quiz data ("+strings.Join(quiz.Formats(), ", ")+"). "+
+			"Defaults to the format of each file extension.",
+	)
+
+	flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{DEFAULT_FILEPATH}
+	}
+
+	failed := false
+	for _, path := range paths {
+
+		diagnostics, err := quiz.LintFile(path, format)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+
+		for _, d := range diagnostics {
+			fmt.Println(d)
+		}
+		if len(diagnostics) > 0 {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
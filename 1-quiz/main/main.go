@@ -1,15 +1,20 @@
 package main
 
 import (
-	"bufio"
-	"encoding/csv"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"os"
+	"os/signal"
+	"quiz"
+	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/term"
 )
 
 /*
@@ -21,182 +26,681 @@ Sources
 - https://gobyexample.com/command-line-flags
 - https://go.dev/tour/concurrency/1
 - https://gobyexample.com/timeouts
+- https://pkg.go.dev/encoding/json#Decoder.InputOffset
+- https://pkg.go.dev/gopkg.in/yaml.v3#Node
+- https://www.markdownguide.org/extended-syntax/#tables
+- https://en.wikipedia.org/wiki/Levenshtein_distance
+- https://pkg.go.dev/math/rand#New
+- https://pkg.go.dev/go.etcd.io/bbolt
+- https://pkg.go.dev/flag#FlagSet
+- https://super-memory.com/english/ol/sm2.htm
+- https://pkg.go.dev/github.com/gorilla/websocket
+- https://pkg.go.dev/math/big#Rat
+- https://en.wikipedia.org/wiki/Elo_rating_system
+- https://pkg.go.dev/os/signal#NotifyContext
+- https://github.com/testmoapp/junitxml
+- https://pkg.go.dev/golang.org/x/text/message
+- https://go.dev/blog/normalization
+- https://en.wikipedia.org/wiki/ANSI_escape_code
+- https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/ETag
+- https://pkg.go.dev/io/fs
+- https://pkg.go.dev/crypto/hmac
+- https://en.wikipedia.org/wiki/Bracketed-paste
+- https://en.wikipedia.org/wiki/Single-elimination_tournament
 */
 
 var DEFAULT_FILEPATH = "problems.csv"
+var DEFAULT_CACHE = quiz.DefaultCacheDir()
 var DEFAULT_TIME_LIMIT = 30
 var DEFAULT_SHUFFLE = false
+var DEFAULT_STRATIFY = false
+var DEFAULT_SEED int64 = 0
+var DEFAULT_COUNT = 0
+var DEFAULT_STUDY = false
+var DEFAULT_ADAPTIVE = false
+var DEFAULT_RESUME = false
+var DEFAULT_SESSION = "session.json"
+var DEFAULT_LOCALE = quiz.DetectLocale()
+var DEFAULT_TUI = false
+var DEFAULT_TAGS = ""
+var DEFAULT_EXCLUDE_TAGS = ""
+var DEFAULT_PROCTOR = false
+var DEFAULT_HIDE_ANSWERED = false
+var DEFAULT_KEY = "quiz.key"
+var DEFAULT_HISTORY = "history.db"
+var DEFAULT_USER = defaultUser()
+
+// keyPath is the file of the key that signed reports are signed
+// with, and that the hash chains of recorded answers are keyed
+// with, set by the -key flag.
+var keyPath = DEFAULT_KEY
+
+func init() {
+	quiz.RegisterReport("signed", writeSignedReport, ".signed")
+}
+
+// printer writes messages in the catalog of the -locale.
+var printer = quiz.NewPrinter(DEFAULT_LOCALE)
 
-// main executes the quiz game.
+// main executes the quiz game, or the subcommand named by the
+// first argument.
 func main() {
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "stats":
+			runStats(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "host":
+			runHost(os.Args[2:])
+			return
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		case "tournament":
+			runTournament(os.Args[2:])
+			return
+		}
+	}
+
+	runQuiz(os.Args[1:])
+}
+
+// defaultUser returns the name of the current user, or
+// "anonymous" if it is unknown.
+func defaultUser() string {
+
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+
+	return "anonymous"
+}
+
+// loadProblems loads the quiz data from the specified filepath,
+// URL or bundle and sets the matcher of questions that do not
+// specify one. Any malformed rows are reported before exiting.
+func loadProblems(filepath string, format string, match string, cacheDir string) []quiz.Problem {
+
+	problems, err := quiz.LoadSource(filepath, format, quiz.NewCache(cacheDir))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if _, err := quiz.ParseMatcher(match); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	quiz.SetDefaultMatch(problems, match)
+
+	return problems
+}
+
+// runQuiz executes the quiz game and records the result in the
+// history.
+func runQuiz(args []string) {
+
+	flags := flag.NewFlagSet("quiz", flag.ExitOnError)
+
 	var filepath string
-	flag.StringVar(
+	flags.StringVar(
 		&filepath,
 		"filepath",
 		DEFAULT_FILEPATH,
-		"Filepath (global) to quiz data.",
+		"Filepath (global), http(s) URL or bundle (directory or .zip, optionally #bank) of quiz data.",
+	)
+
+	var format string
+	flags.StringVar(
+		&format,
+		"format",
+		"",
+		"Format of the quiz data ("+strings.Join(quiz.Formats(), ", ")+"). "+
+			"Defaults to the format of the file extension.",
+	)
+
+	var cacheDir string
+	flags.StringVar(
+		&cacheDir,
+		"cache",
+		DEFAULT_CACHE,
+		"Directory quiz data fetched from a URL is cached in.",
+	)
+
+	var match string
+	flags.StringVar(
+		&match,
+		"match",
+		quiz.DEFAULT_MATCH,
+		"Answer matcher for questions that do not specify one ("+
+			strings.Join(quiz.Matchers(), ", ")+"), e.g. fuzzy:2.",
 	)
 
 	var shuffleFlag bool
-	flag.BoolVar(
+	flags.BoolVar(
 		&shuffleFlag,
 		"shuffle",
 		DEFAULT_SHUFFLE,
 		"Shuffle the quiz data.",
 	)
 
+	var stratifyFlag bool
+	flags.BoolVar(
+		&stratifyFlag,
+		"stratify",
+		DEFAULT_STRATIFY,
+		"Shuffle only within each question category (implies -shuffle).",
+	)
+
+	var seed int64
+	flags.Int64Var(
+		&seed,
+		"seed",
+		DEFAULT_SEED,
+		"Seed for shuffling, to replay a quiz (0 chooses one at random).",
+	)
+
+	var count int
+	flags.IntVar(
+		&count,
+		"count",
+		DEFAULT_COUNT,
+		"Number of questions to draw at random from the quiz data (0 for all).",
+	)
+
 	var timeLimit int
-	flag.IntVar(
+	flags.IntVar(
 		&timeLimit,
 		"time_limit",
 		DEFAULT_TIME_LIMIT,
 		"Quiz duration (in seconds).",
 	)
 
-	flag.Parse()
+	var user string
+	flags.StringVar(
+		&user,
+		"user",
+		DEFAULT_USER,
+		"Name the result is recorded under in the history.",
+	)
+
+	var historyPath string
+	flags.StringVar(
+		&historyPath,
+		"history",
+		DEFAULT_HISTORY,
+		"Filepath to the history database (empty to not record results).",
+	)
 
-	data := getCSVData(filepath)
+	var studyFlag bool
+	flags.BoolVar(
+		&studyFlag,
+		"study",
+		DEFAULT_STUDY,
+		"Only ask the questions due for review by spaced repetition (requires -history).",
+	)
 
-	shuffleText := "Shuffling is off.\n"
-	if shuffleFlag {
-		data = shuffleCSVData(data)
-		shuffleText = "Shuffling is on.\n"
-	}
+	var adaptiveFlag bool
+	flags.BoolVar(
+		&adaptiveFlag,
+		"adaptive",
+		DEFAULT_ADAPTIVE,
+		"Choose harder or easier questions as you answer (-count limits how many are asked).",
+	)
 
-	fmt.Printf(
-		strings.Join(
-			[]string{
-				"You are about to take a quiz.\n",
-				"The timer is set to %d seconds.\n",
-				shuffleText,
-				"Please press enter to begin.\n",
-			},
-			"",
-		),
-		timeLimit,
+	var resumeFlag bool
+	flags.BoolVar(
+		&resumeFlag,
+		"resume",
+		DEFAULT_RESUME,
+		"Resume the quiz that was interrupted and saved to -session.",
 	)
 
-	userResponse := getUserInput()
+	var sessionPath string
+	flags.StringVar(
+		&sessionPath,
+		"session",
+		DEFAULT_SESSION,
+		"Filepath an interrupted quiz is saved to.",
+	)
 
-	if userResponse != "\n" {
-		fmt.Println("Text detected; terminating quiz.")
-		os.Exit(0)
-	}
+	var reports stringList
+	flags.Var(
+		&reports,
+		"report",
+		"Filepath to write a report of the answers to, by extension ("+
+			strings.Join(quiz.Reports(), ", ")+" prefixed to the path, e.g. junit:report.xml). "+
+			"May be repeated.",
+	)
+
+	var locale string
+	flags.StringVar(
+		&locale,
+		"locale",
+		DEFAULT_LOCALE,
+		"Locale of the messages ("+strings.Join(quiz.Locales(), ", ")+"). "+
+			"Defaults to the locale of the environment.",
+	)
 
-	score := executeQuiz(data, timeLimit)
+	var tuiFlag bool
+	flags.BoolVar(
+		&tuiFlag,
+		"tui",
+		DEFAULT_TUI,
+		"Show the quiz full-screen with a countdown bar, when run in a terminal.",
+	)
 
-	fmt.Printf("\nYou scored %d out of %d.\n", score, len(data))
-}
+	var tags string
+	flags.StringVar(
+		&tags,
+		"tags",
+		DEFAULT_TAGS,
+		"Comma-separated tags or categories; only questions with one of them are asked.",
+	)
+
+	var excludeTags string
+	flags.StringVar(
+		&excludeTags,
+		"exclude_tags",
+		DEFAULT_EXCLUDE_TAGS,
+		"Comma-separated tags or categories of questions that are not asked.",
+	)
+
+	var quotaSpecs stringList
+	flags.Var(
+		&quotaSpecs,
+		"quota",
+		"Most questions to draw at random from a category, e.g. arithmetic=5. May be repeated.",
+	)
+
+	var proctorFlag bool
+	flags.BoolVar(
+		&proctorFlag,
+		"proctor",
+		DEFAULT_PROCTOR,
+		"Flag answers given implausibly fast or pasted in, e.g. for certification.",
+	)
+
+	var hideAnsweredFlag bool
+	flags.BoolVar(
+		&hideAnsweredFlag,
+		"hide_answered",
+		DEFAULT_HIDE_ANSWERED,
+		"Clear the terminal before each question so answered questions cannot be read back.",
+	)
+
+	flags.StringVar(
+		&keyPath,
+		"key",
+		DEFAULT_KEY,
+		"Filepath to the key signed reports and recorded answers are keyed with (created if it does not exist).",
+	)
 
-// getUserInput uses Buffered I/O to read in user input from the command line.
-// This allows for user input with spaces.
-func getUserInput() string {
+	flags.Parse(args)
 
-	in := bufio.NewReader(os.Stdin)
+	printer = quiz.NewPrinter(locale)
 
-	result, err := in.ReadString('\n')
+	quotas, err := quiz.ParseQuotas(quotaSpecs)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	return result
-}
+	// A resumed quiz takes its settings from when it was saved.
+	var saved *savedQuiz
+	if resumeFlag {
+		var err error
+		saved, err = loadSavedQuiz(sessionPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		filepath, user = saved.Source, saved.User
+		studyFlag, adaptiveFlag = saved.Study, saved.Adaptive
+	}
+
+	var history *quiz.History
+	if historyPath != "" {
+		history, err = quiz.OpenHistory(historyPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer history.Close()
+	}
 
-// getCSVData reads in a CSV file from the specified filepath
-// and returns the data.
-func getCSVData(filepath string) [][]string {
+	var reviews map[string]quiz.Review
+	if studyFlag {
+		if history == nil {
+			log.Fatal("-study requires a -history database")
+		}
+		reviews, err = history.Reviews(user)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	f, err := os.Open(filepath)
-	if err != nil {
+	// Adaptive questions are drawn from every problem, so -count
+	// limits how many are asked rather than sampling them.
+	var adaptive *quiz.Adaptive
+	sample := count
+	if adaptiveFlag {
+		adaptive = quiz.NewAdaptive()
+		sample = 0
+	}
+
+	var q *quiz.Quiz
+	if saved != nil {
+		q = &quiz.Quiz{Locale: locale}
+		if proctorFlag {
+			q.Proctor = quiz.NewProctor()
+		}
+		if adaptive != nil {
+			q.Policy = adaptive
+		}
+		printer.Printf(
+			"You are about to resume a quiz with %d questions left.\n",
+			len(saved.Session.Remaining),
+		)
+		if timeLeft := saved.Session.TimeLeft; timeLeft > 0 {
+			printer.Printf("The timer has %d seconds left.\n", int(timeLeft.Seconds()))
+		}
+	} else {
+		problems := quiz.FilterTags(
+			loadProblems(filepath, format, match, cacheDir),
+			splitTags(tags), splitTags(excludeTags),
+		)
+		if len(problems) == 0 {
+			log.Fatal("no questions match -tags and -exclude_tags")
+		}
+		if err := quiz.CheckQuotas(problems, quotas); err != nil {
+			log.Fatal(err)
+		}
+		var shuffleText string
+		q, shuffleText = newQuiz(
+			problems, sample, quotas, seed,
+			shuffleFlag, stratifyFlag, studyFlag, reviews,
+		)
+		q.TimeLimit = time.Duration(timeLimit) * time.Second
+		q.Locale = locale
+		if proctorFlag {
+			q.Proctor = quiz.NewProctor()
+		}
+		if adaptive != nil {
+			q.Policy = adaptive
+			q.Questions = count
+		}
+		printer.Printf("You are about to take a quiz.\n")
+		printer.Printf("The timer is set to %d seconds.\n", timeLimit)
+		printer.Print(shuffleText)
+	}
+
+	// Answers that are recorded are linked into a hash chain
+	// keyed as they are submitted, so that the history and the
+	// reports cannot be changed without the key.
+	if historyPath != "" || len(reports) > 0 {
+		if q.Key, err = quiz.LoadKey(keyPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	printer.Printf("Enter %s to pause the timer.\n", quiz.PAUSE_COMMAND)
+	printer.Printf("Please press enter to begin.\n")
+
+	// Interrupting the quiz saves it to be resumed, after which
+	// a second interrupt exits as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	input := quiz.NewInput(os.Stdin)
+	defer input.Close()
+
+	if err := input.WaitForEnter(ctx); err != nil {
+		if err == quiz.ErrAborted {
+			printer.Printf("Text detected; terminating quiz.\n")
+			os.Exit(0)
+		}
+		if err == context.Canceled {
+			return
+		}
 		log.Fatal(err)
 	}
 
-	defer f.Close()
+	var session *quiz.Session
+	if saved != nil {
+		session = q.Resume(saved.Session, quiz.RealClock{})
+	} else {
+		session = q.Start(quiz.RealClock{})
+	}
+
+	// A proctored quiz has the terminal mark pasted text.
+	pasteMarked := proctorFlag && term.IsTerminal(int(os.Stdout.Fd()))
+	if pasteMarked {
+		fmt.Print(quiz.BRACKETED_PASTE_ON)
+	}
 
-	csvReader := csv.NewReader(f)
-	data, err := csvReader.ReadAll()
+	view := newView(tuiFlag)
+	tui, _ := view.(*quiz.TerminalView)
+	if hideAnsweredFlag {
+		view = quiz.HideAnswered(view, os.Stdout)
+	}
+	err = session.Play(ctx, input, view)
+	if tui != nil {
+		tui.Close()
+	}
+	if pasteMarked {
+		fmt.Print(quiz.BRACKETED_PASTE_OFF)
+	}
+	stop()
+	result := session.Result()
+
+	if err == context.Canceled {
+		fmt.Println()
+		printer.Printf("Quiz interrupted.\n")
+		printSummary(result, adaptive)
+		err := saveQuiz(sessionPath, savedQuiz{
+			Source:   filepath,
+			User:     user,
+			Study:    studyFlag,
+			Adaptive: adaptiveFlag,
+			Session:  session.Save(),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		printer.Printf("Resume this quiz with -resume -session %s.\n", sessionPath)
+		return
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	return data
-}
+	fmt.Println()
+	printSummary(result, adaptive)
+	printer.Printf("Replay this quiz with -seed %s.\n", strconv.FormatInt(result.Seed, 10))
 
-// shuffleCSVData returns a copy of the data but shuffled
-// psuedo-randomly using the default seed.
-func shuffleCSVData(CSVData [][]string) [][]string {
+	if saved != nil {
+		if err := os.Remove(sessionPath); err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	var data [][]string
-	copy(data, CSVData)
+	attempt := quiz.NewAttempt(user, filepath, result)
+	for _, report := range reports {
+		if err := quiz.WriteReport(report, attempt); err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	rand.Shuffle(
-		len(data),
-		func(i int, j int) {
-			data[i], data[j] = data[j], data[i]
-		},
-	)
+	if history == nil {
+		return
+	}
+	if err := history.Save(attempt); err != nil {
+		log.Fatal(err)
+	}
 
-	return data
+	if studyFlag {
+		quiz.UpdateReviews(reviews, result.Answers, time.Now())
+		if err := history.SaveReviews(user, reviews); err != nil {
+			log.Fatal(err)
+		}
+		printer.Printf(
+			"The next review is due %s.\n",
+			quiz.NextDue(reviews).Format("2006-01-02 15:04"),
+		)
+	}
 }
 
-// executeQuiz loops over the specified data, displaying the first
-// row-attribute as the 'question', awaits user input, and then compares said
-// input with the second row-attribute as the 'answer'.
-//
-// If the user input matches the 'answer' the running total, or score, of
-// correct answers is incremented. Otherwise, the loop continues.
-//
-// The loop is terminated if the function execution time exceeds the
-// 'timeLimit'.
-func executeQuiz(data [][]string, timeLimit int) int {
-
-	resultChannel := make(chan int, 1)
-	timeoutChannel := time.After(time.Duration(timeLimit) * time.Second)
-
-	score := 0
-	for i, row := range data {
-
-		fmt.Printf("%d. %s?\n", i+1, row[0])
-
-		// A goroutine that prompts the user with a quiz question.
-		//
-		// The user response and the answer to the corresponding quiz question
-		// are stripped of formatting to ensure a valid comparison.
-		//
-		// This is done in a goroutine to allow for a timeout.
-		go func() {
-
-			userResponse := getUserInput()
-
-			processedUserResponse := strings.ReplaceAll(
-				strings.Trim(strings.ToLower(userResponse), "\n"), " ", "",
+// newQuiz selects the problems to be asked, within the quota of
+// each category, and the order to ask them in. It returns the
+// Quiz along with a description, in the -locale, of how it was
+// shuffled, or exits in study mode if no problems are due.
+func newQuiz(
+	problems []quiz.Problem,
+	count int,
+	quotas map[string]int,
+	seed int64,
+	shuffleFlag bool,
+	stratifyFlag bool,
+	studyFlag bool,
+	reviews map[string]quiz.Review,
+) (*quiz.Quiz, string) {
+
+	if studyFlag {
+		problems = quiz.DueProblems(problems, reviews, time.Now())
+		if len(problems) == 0 {
+			printer.Printf(
+				"No questions are due for review until %s.\n",
+				quiz.NextDue(reviews).Format("2006-01-02 15:04"),
 			)
+			os.Exit(0)
+		}
 
-			processedAnswer := strings.ReplaceAll(
-				strings.ToLower(row[1]), " ", "",
-			)
+		// The most overdue questions are studied first.
+		if count > 0 && count < len(problems) {
+			problems = problems[:count]
+		}
+	}
 
-			result := 0
-			if processedUserResponse == processedAnswer {
-				result = 1
-			}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	problems = quiz.ApplyQuotas(problems, quotas, rng)
+	if count > 0 && !studyFlag {
+		problems = quiz.Sample(problems, count, rng)
+	}
+
+	shuffleText := printer.Sprintf("Shuffling is off.\n")
+	if stratifyFlag {
+		problems = quiz.ShuffleWithinCategories(problems, rng)
+		shuffleText = printer.Sprintf("Shuffling is on (within categories).\n")
+	} else if shuffleFlag {
+		problems = quiz.Shuffle(problems, rng)
+		shuffleText = printer.Sprintf("Shuffling is on.\n")
+	}
 
-			resultChannel <- result
-		}()
+	return &quiz.Quiz{Problems: problems, Seed: seed}, shuffleText
+}
 
-		select {
+// newView returns the full-screen view if it was asked for and
+// standard output is a terminal, and the line view otherwise.
+// Code snippets are highlighted only in a terminal.
+func newView(tuiFlag bool) quiz.View {
 
-		case result := <-resultChannel:
-			score += result
+	fd := int(os.Stdout.Fd())
+	color := term.IsTerminal(fd)
+	if !tuiFlag {
+		return quiz.NewLineView(os.Stdout, printer, color)
+	}
+	if !color {
+		fmt.Fprintln(os.Stderr, "Standard output is not a terminal; -tui is ignored.")
+		return quiz.NewLineView(os.Stdout, printer, false)
+	}
 
-		case <-timeoutChannel:
-			fmt.Println("Time's up!")
-			return score
+	width, _, err := term.GetSize(fd)
+	if err != nil || width <= 0 {
+		width = 80
+	}
 
+	return quiz.NewTerminalView(os.Stdout, printer, width)
+}
+
+// printSummary prints the score of the result, broken down by
+// category if any question has one, how many answers were
+// flagged and, for an adaptive quiz, the estimated skill rating.
+func printSummary(result quiz.Result, adaptive *quiz.Adaptive) {
+
+	printer.Printf(
+		"You scored %g out of %g points (%d of %d correct).\n",
+		result.Score, result.MaxScore, result.Correct, result.Total,
+	)
+	scores := result.ByCategory()
+	if len(scores) > 1 || len(scores) == 1 && scores[0].Category != "" {
+		for _, score := range scores {
+			category := score.Category
+			if category == "" {
+				category = printer.Sprintf("Uncategorized")
+			}
+			printer.Printf(
+				"  %s: %g out of %g points (%d of %d correct)\n",
+				category, score.Score, score.MaxScore, score.Correct, score.Total,
+			)
 		}
+	}
+	if flagged := result.Flagged(); flagged > 0 {
+		printer.Printf("%d answers were flagged as given too fast or pasted in.\n", flagged)
+	}
+	if adaptive != nil {
+		printer.Printf(
+			"Your estimated skill rating is %.1f (1 is easy, 2 medium and 3 hard).\n",
+			adaptive.Rating(result.Answers),
+		)
+	}
+}
+
+// writeSignedReport is the Reporter of signed reports, which
+// are signed with the key at the keyPath.
+func writeSignedReport(w io.Writer, attempt quiz.Attempt) error {
+
+	key, err := quiz.LoadKey(keyPath)
+	if err != nil {
+		return err
+	}
 
+	return quiz.WriteSignedReport(w, attempt, key)
+}
+
+// splitTags splits a comma-separated list of tags.
+func splitTags(text string) []string {
+
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
 	}
 
-	return score
+	return tags
+}
+
+// stringList is a flag that may be repeated, collecting every
+// value it is given.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"quiz"
+	"strings"
+	"time"
+)
+
+var DEFAULT_ADDR = ":8080"
+
+// runServe executes a local server that gives the quiz via HTML
+// pages and a JSON API.
+func runServe(args []string) {
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+
+	var filepath string
+	flags.StringVar(
+		&filepath,
+		"filepath",
+		DEFAULT_FILEPATH,
+		"Filepath (global), http(s) URL or bundle (directory or .zip, optionally #bank) of quiz data.",
+	)
+
+	var format string
+	flags.StringVar(
+		&format,
+		"format",
+		"",
+		"Format of the quiz data ("+strings.Join(quiz.Formats(), ", ")+"). "+
+			"Defaults to the format of the file extension.",
+	)
+
+	var cacheDir string
+	flags.StringVar(
+		&cacheDir,
+		"cache",
+		DEFAULT_CACHE,
+		"Directory quiz data fetched from a URL is cached in.",
+	)
+
+	var match string
+	flags.StringVar(
+		&match,
+		"match",
+		quiz.DEFAULT_MATCH,
+		"Answer matcher for questions that do not specify one ("+
+			strings.Join(quiz.Matchers(), ", ")+"), e.g. fuzzy:2.",
+	)
+
+	var shuffleFlag bool
+	flags.BoolVar(
+		&shuffleFlag,
+		"shuffle",
+		DEFAULT_SHUFFLE,
+		"Shuffle the quiz data for each session.",
+	)
+
+	var timeLimit int
+	flags.IntVar(
+		&timeLimit,
+		"time_limit",
+		DEFAULT_TIME_LIMIT,
+		"Quiz duration (in seconds).",
+	)
+
+	var addr string
+	flags.StringVar(
+		&addr,
+		"addr",
+		DEFAULT_ADDR,
+		"Address to serve the quiz on.",
+	)
+
+	flags.Parse(args)
+
+	q := &quiz.Quiz{
+		Problems:  loadProblems(filepath, format, match, cacheDir),
+		TimeLimit: time.Duration(timeLimit) * time.Second,
+	}
+
+	assets, closer, err := quiz.OpenAssets(filepath, quiz.NewCache(cacheDir))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	server := quiz.NewServer(q, quiz.RealClock{})
+	server.Shuffle = shuffleFlag
+	server.Assets = assets
+
+	fmt.Printf("Starting the server on %s\n", addr)
+	log.Fatal(http.ListenAndServe(addr, server))
+}
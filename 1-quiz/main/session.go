@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"quiz"
+)
+
+// savedQuiz is an interrupted quiz as it is saved to be resumed,
+// along with the settings it was taken with.
+type savedQuiz struct {
+	Source   string            `json:"source"`
+	User     string            `json:"user"`
+	Study    bool              `json:"study"`
+	Adaptive bool              `json:"adaptive"`
+	Session  quiz.SavedSession `json:"session"`
+}
+
+// loadSavedQuiz reads a quiz saved by saveQuiz.
+func loadSavedQuiz(path string) (*savedQuiz, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved savedQuiz
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// saveQuiz writes the quiz to the specified path as JSON.
+func saveQuiz(path string, saved savedQuiz) error {
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"quiz"
+	"time"
+)
+
+var DEFAULT_TOP = 5
+
+// runStats prints a user's trend, weakest questions and average
+// time per question from the history.
+func runStats(args []string) {
+
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+
+	var user string
+	flags.StringVar(
+		&user,
+		"user",
+		DEFAULT_USER,
+		"Name of the user to show statistics for.",
+	)
+
+	var historyPath string
+	flags.StringVar(
+		&historyPath,
+		"history",
+		DEFAULT_HISTORY,
+		"Filepath to the history database.",
+	)
+
+	var top int
+	flags.IntVar(
+		&top,
+		"top",
+		DEFAULT_TOP,
+		"Number of weakest questions to show.",
+	)
+
+	flags.Parse(args)
+
+	history, err := quiz.OpenHistory(historyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer history.Close()
+
+	attempts, err := history.Attempts(user)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(attempts) == 0 {
+		fmt.Printf("No quizzes recorded for %q.\n", user)
+		return
+	}
+
+	stats := quiz.ComputeStats(attempts)
+
+	fmt.Printf("Statistics for %s (%d quizzes)\n\n", user, len(attempts))
+
+	fmt.Println("Recent quizzes:")
+	recent := attempts
+	if len(recent) > 10 {
+		recent = recent[len(recent)-10:]
+	}
+	for _, a := range recent {
+		fmt.Printf(
+			"  %s  %5.1f%%  %g/%g  %s\n",
+			a.Started.Format("2006-01-02 15:04"), a.Percent(), a.Score, a.MaxScore, a.Source,
+		)
+	}
+	fmt.Printf("Trend: %+.1f%%\n\n", stats.Trend())
+
+	fmt.Println("Weakest questions:")
+	for i, qs := range stats.Questions {
+		if i == top {
+			break
+		}
+		fmt.Printf(
+			"  %-30s %3.0f%% correct (%d/%d), %s on average\n",
+			qs.Question, 100*qs.Accuracy(), qs.Correct, qs.Asked,
+			qs.AverageLatency().Round(100*time.Millisecond),
+		)
+	}
+
+	fmt.Printf(
+		"\nAverage time per question: %s\n",
+		stats.AverageLatency.Round(100*time.Millisecond),
+	)
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"quiz"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var DEFAULT_TOURNAMENT = "tournament.json"
+
+// TOURNAMENT_USAGE lists the actions of the tournament subcommand.
+const TOURNAMENT_USAGE = `Usage: quiz tournament <action> [flags] [arguments]
+
+Actions:
+  new <name>                  Start a tournament.
+  register <team> <player>... Register players into a team.
+  round [flags]               Play a round, deciding the current matches of the bracket.
+  bracket                     Draw the elimination bracket from the standings.
+  standings                   Print the standings and the bracket.
+  report [flags]              Export the standings, bracket and rounds as CSV or HTML.
+
+Run quiz tournament <action> -h for the flags of an action.
+`
+
+// runTournament runs the action of the tournament subcommand
+// named by the first argument. The tournament is kept in a file
+// between actions.
+func runTournament(args []string) {
+
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, TOURNAMENT_USAGE)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "new":
+		runTournamentNew(args[1:])
+	case "register":
+		runTournamentRegister(args[1:])
+	case "round":
+		runTournamentRound(args[1:])
+	case "bracket":
+		runTournamentBracket(args[1:])
+	case "standings":
+		runTournamentStandings(args[1:])
+	case "report":
+		runTournamentReport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown tournament action %q.\n\n", args[0])
+		fmt.Fprint(os.Stderr, TOURNAMENT_USAGE)
+		os.Exit(2)
+	}
+}
+
+// tournamentFlags returns the flags of a tournament action, with
+// the -state flag already set up, and the usage line of the
+// action's arguments.
+func tournamentFlags(action string, arguments string) (*flag.FlagSet, *string) {
+
+	flags := flag.NewFlagSet("tournament "+action, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: quiz tournament %s [flags] %s\n", action, arguments)
+		flags.PrintDefaults()
+	}
+
+	var statePath string
+	flags.StringVar(
+		&statePath,
+		"state",
+		DEFAULT_TOURNAMENT,
+		"Filepath the tournament is kept in between rounds.",
+	)
+
+	return flags, &statePath
+}
+
+// loadTournament loads the tournament from the specified path,
+// exiting if it cannot.
+func loadTournament(path string) *quiz.Tournament {
+
+	t, err := quiz.LoadTournament(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("%s does not exist; start a tournament with quiz tournament new", path)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return t
+}
+
+// runTournamentNew starts a tournament.
+func runTournamentNew(args []string) {
+
+	flags, statePath := tournamentFlags("new", "<name>")
+
+	var force bool
+	flags.BoolVar(
+		&force,
+		"force",
+		false,
+		"Replace the tournament already kept in -state.",
+	)
+
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if _, err := os.Stat(*statePath); err == nil && !force {
+		log.Fatalf("%s already exists; use -force to replace it", *statePath)
+	}
+
+	t := quiz.NewTournament(flags.Arg(0))
+	if err := t.Save(*statePath); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Started %s in %s.\n", t.Name, *statePath)
+}
+
+// runTournamentRegister registers players into a team.
+func runTournamentRegister(args []string) {
+
+	flags, statePath := tournamentFlags("register", "<team> <player>...")
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	t := loadTournament(*statePath)
+	team := flags.Arg(0)
+	if err := t.Register(team, flags.Args()[1:]...); err != nil {
+		log.Fatal(err)
+	}
+	if err := t.Save(*statePath); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, registered := range t.Teams {
+		if registered.Name == team {
+			fmt.Printf("%s: %s\n", team, strings.Join(registered.Players, ", "))
+		}
+	}
+}
+
+// runTournamentBracket draws the elimination bracket.
+func runTournamentBracket(args []string) {
+
+	flags, statePath := tournamentFlags("bracket", "")
+	flags.Parse(args)
+
+	t := loadTournament(*statePath)
+	if err := t.DrawBracket(); err != nil {
+		log.Fatal(err)
+	}
+	if err := t.Save(*statePath); err != nil {
+		log.Fatal(err)
+	}
+
+	printBracket(t)
+}
+
+// runTournamentStandings prints the standings and the bracket.
+func runTournamentStandings(args []string) {
+
+	flags, statePath := tournamentFlags("standings", "")
+	flags.Parse(args)
+
+	t := loadTournament(*statePath)
+	printStandings(t)
+	if len(t.Bracket) > 0 {
+		fmt.Println()
+		printBracket(t)
+	}
+}
+
+// runTournamentReport exports the tournament as CSV or HTML.
+func runTournamentReport(args []string) {
+
+	flags, statePath := tournamentFlags("report", "")
+
+	var csvPath string
+	flags.StringVar(
+		&csvPath,
+		"csv",
+		"",
+		"Filepath to write the standings to as CSV.",
+	)
+
+	var htmlPath string
+	flags.StringVar(
+		&htmlPath,
+		"html",
+		"",
+		"Filepath to write the standings, bracket and rounds to as HTML.",
+	)
+
+	flags.Parse(args)
+
+	if csvPath == "" && htmlPath == "" {
+		log.Fatal("expected -csv or -html")
+	}
+
+	t := loadTournament(*statePath)
+	reports := []struct {
+		path  string
+		write func(f *os.File) error
+	}{
+		{csvPath, func(f *os.File) error { return quiz.WriteTournamentCSV(f, t) }},
+		{htmlPath, func(f *os.File) error { return quiz.WriteTournamentHTML(f, t) }},
+	}
+	for _, report := range reports {
+		if report.path == "" {
+			continue
+		}
+		f, err := os.Create(report.path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := report.write(f); err != nil {
+			f.Close()
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// runTournamentRound has every player of the teams still in the
+// tournament take the same quiz in turn, drawn from one or more
+// problem banks, and records the round. The tournament is only
+// changed once every player has finished.
+func runTournamentRound(args []string) {
+
+	flags, statePath := tournamentFlags("round", "")
+
+	var sources stringList
+	flags.Var(
+		&sources,
+		"filepath",
+		"Filepath, http(s) URL or bundle of quiz data (default "+DEFAULT_FILEPATH+"). "+
+			"May be repeated to draw from several problem banks.",
+	)
+
+	var format string
+	flags.StringVar(
+		&format,
+		"format",
+		"",
+		"Format of the quiz data ("+strings.Join(quiz.Formats(), ", ")+"). "+
+			"Defaults to the format of the file extension.",
+	)
+
+	var cacheDir string
+	flags.StringVar(
+		&cacheDir,
+		"cache",
+		DEFAULT_CACHE,
+		"Directory quiz data fetched from a URL is cached in.",
+	)
+
+	var match string
+	flags.StringVar(
+		&match,
+		"match",
+		quiz.DEFAULT_MATCH,
+		"Answer matcher for questions that do not specify one ("+
+			strings.Join(quiz.Matchers(), ", ")+"), e.g. fuzzy:2.",
+	)
+
+	var count int
+	flags.IntVar(
+		&count,
+		"count",
+		DEFAULT_COUNT,
+		"Number of questions to draw at random from the quiz data (0 for all).",
+	)
+
+	var seed int64
+	flags.Int64Var(
+		&seed,
+		"seed",
+		DEFAULT_SEED,
+		"Seed for drawing and shuffling the questions (0 chooses one at random).",
+	)
+
+	var timeLimit int
+	flags.IntVar(
+		&timeLimit,
+		"time_limit",
+		DEFAULT_TIME_LIMIT,
+		"Duration of each player's quiz (in seconds).",
+	)
+
+	flags.Parse(args)
+
+	t := loadTournament(*statePath)
+	teams := t.Playing()
+	if champion, ok := t.Champion(); ok {
+		log.Fatalf("the tournament is over; %s won", champion)
+	}
+	if len(teams) == 0 {
+		log.Fatal("no teams are registered")
+	}
+
+	if len(sources) == 0 {
+		sources = stringList{DEFAULT_FILEPATH}
+	}
+	var problems []quiz.Problem
+	for _, source := range sources {
+		problems = append(problems, loadProblems(source, format, match, cacheDir)...)
+	}
+
+	// Every player is asked the same questions in the same order.
+	q, _ := newQuiz(problems, count, nil, seed, true, false, false, nil)
+	q.TimeLimit = time.Duration(timeLimit) * time.Second
+
+	round := quiz.Round{Sources: sources, Seed: q.Seed, Played: time.Now()}
+	fmt.Printf("Round %d of %s: %d questions, %d seconds each.\n", len(t.Rounds)+1, t.Name, len(q.Problems), timeLimit)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	input := quiz.NewInput(os.Stdin)
+	defer input.Close()
+
+	color := term.IsTerminal(int(os.Stdout.Fd()))
+	for _, team := range teams {
+		for _, player := range team.Players {
+
+			fmt.Printf("\n%s (%s), please press enter to begin.\n", player, team.Name)
+			err := input.WaitForEnter(ctx)
+			if err == quiz.ErrAborted {
+				err = nil
+			}
+			if err == nil {
+				session := q.Start(quiz.RealClock{})
+				err = session.Play(ctx, input, quiz.NewLineView(os.Stdout, printer, color))
+				if err == nil {
+					result := session.Result()
+					fmt.Println()
+					printSummary(result, nil)
+					round.Scores = append(round.Scores, quiz.NewPlayerScore(player, team.Name, result))
+				}
+			}
+			if err == context.Canceled {
+				fmt.Println()
+				fmt.Println("Round abandoned; the tournament was not changed.")
+				return
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	round = t.AddRound(round)
+	if err := t.Save(*statePath); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("\nRound %d results:\n", round.Number)
+	for _, m := range t.Bracket {
+		if m.Round == round.Number {
+			fmt.Printf("  %s beat %s\n", m.Winner, opponent(m))
+		}
+	}
+	fmt.Println()
+	printStandings(t)
+	if champion, ok := t.Champion(); ok {
+		fmt.Printf("\n%s won the tournament!\n", champion)
+	}
+}
+
+// opponent returns the team that lost the decided Match.
+func opponent(m quiz.Match) string {
+
+	if m.Winner == m.Home {
+		return m.Away
+	}
+
+	return m.Home
+}
+
+// printStandings prints the aggregate of each team.
+func printStandings(t *quiz.Tournament) {
+
+	fmt.Printf("Standings of %s after %d rounds:\n", t.Name, len(t.Rounds))
+	for i, s := range t.Standings() {
+		status := ""
+		if s.Eliminated {
+			status = "  (eliminated)"
+		}
+		fmt.Printf(
+			"  %2d. %-20s %5.1f%%  %g/%g  %d of %d correct%s\n",
+			i+1, s.Team, s.Percent(), s.Score, s.MaxScore, s.Correct, s.Total, status,
+		)
+	}
+}
+
+// printBracket prints the matches of each stage of the bracket.
+func printBracket(t *quiz.Tournament) {
+
+	fmt.Println("Bracket:")
+	stage := 0
+	for _, m := range t.Bracket {
+		if m.Stage != stage {
+			stage = m.Stage
+			fmt.Printf("  Stage %d\n", stage)
+		}
+		switch {
+		case m.Away == "":
+			fmt.Printf("    %s (bye)\n", m.Home)
+		case m.Winner != "":
+			fmt.Printf("    %s vs %s: %s won in round %d\n", m.Home, m.Away, m.Winner, m.Round)
+		default:
+			fmt.Printf("    %s vs %s\n", m.Home, m.Away)
+		}
+	}
+}
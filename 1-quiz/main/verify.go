@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"quiz"
+)
+
+// runVerify checks the signature and hash chain of each signed
+// report named by the arguments, exiting with a non-zero status
+// if any was changed or signed with another key.
+func runVerify(args []string) {
+
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: quiz verify [flags] report...")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(
+		&keyPath,
+		"key",
+		DEFAULT_KEY,
+		"Filepath to the key the reports were signed with.",
+	)
+
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// A missing key is not created, as nothing could have been
+	// signed with it.
+	if _, err := os.Stat(keyPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	key, err := quiz.LoadKey(keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+
+		attempt, err := verifyReport(path, key)
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			failed = true
+			continue
+		}
+
+		flagged := 0
+		for _, a := range attempt.Answers {
+			if len(a.Flags) > 0 {
+				flagged++
+			}
+		}
+		fmt.Printf(
+			"%s: OK (%s scored %g out of %g, %d answers flagged)\n",
+			path, attempt.User, attempt.Score, attempt.MaxScore, flagged,
+		)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// verifyReport reads the signed report at the specified path and
+// returns its Attempt once it is verified with the key.
+func verifyReport(path string, key []byte) (quiz.Attempt, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return quiz.Attempt{}, err
+	}
+	defer f.Close()
+
+	return quiz.ReadSignedReport(f, key)
+}
@@ -0,0 +1,288 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Matcher decides whether a user response matches an accepted
+// answer to a Problem. A Matcher is shared by every Problem with
+// the same spec, so it must be safe for concurrent use.
+type Matcher interface {
+	Match(response string, answer string) bool
+}
+
+// MatcherFunc is an adapter to allow the use of an ordinary
+// function as a Matcher.
+type MatcherFunc func(response string, answer string) bool
+
+// Match calls f(response, answer).
+func (f MatcherFunc) Match(response string, answer string) bool {
+	return f(response, answer)
+}
+
+// answerValidator is implemented by Matchers that place
+// requirements on how an answer is written, e.g. as a regular
+// expression.
+type answerValidator interface {
+	ValidateAnswer(answer string) error
+}
+
+// MatcherFactory builds a Matcher from the parameter given in
+// a matcher spec, e.g. "0.01" in "numeric:0.01". The parameter
+// is empty if none was given.
+type MatcherFactory func(param string) (Matcher, error)
+
+// DEFAULT_MATCH is the matcher spec used by Problems that do
+// not specify one.
+const DEFAULT_MATCH = "exact"
+
+var matchers = map[string]MatcherFactory{}
+
+// parsed caches the Matcher of each spec checked against, so
+// that e.g. a regular expression is compiled only once.
+var parsed sync.Map
+
+func init() {
+	RegisterMatcher("exact", newExactMatcher)
+	RegisterMatcher("numeric", newNumericMatcher)
+	RegisterMatcher("regex", newRegexMatcher)
+	RegisterMatcher("fuzzy", newFuzzyMatcher)
+}
+
+// RegisterMatcher makes a Matcher available under the specified
+// name for use in matcher specs.
+func RegisterMatcher(name string, factory MatcherFactory) {
+
+	matchers[name] = factory
+	parsed.Range(func(spec, _ interface{}) bool {
+		parsed.Delete(spec)
+		return true
+	})
+}
+
+// Matchers returns the names of all registered Matchers.
+func Matchers() []string {
+
+	var names []string
+	for name := range matchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseMatcher returns the Matcher for the specified spec. A
+// spec is the name of a registered Matcher optionally followed
+// by a colon and a parameter, e.g. "fuzzy:2". An empty spec is
+// DEFAULT_MATCH.
+func ParseMatcher(spec string) (Matcher, error) {
+
+	if spec == "" {
+		spec = DEFAULT_MATCH
+	}
+	name, param := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		name, param = spec[:i], spec[i+1:]
+	}
+
+	factory, ok := matchers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown matcher %q (expected one of %s)",
+			name, strings.Join(Matchers(), ", "),
+		)
+	}
+
+	m, err := factory(strings.TrimSpace(param))
+	if err != nil {
+		return nil, fmt.Errorf("matcher %q: %v", spec, err)
+	}
+
+	return m, nil
+}
+
+// cachedMatcher is ParseMatcher, parsing each spec only once.
+// Matchers are shared between Problems, so they must be safe
+// for concurrent use.
+func cachedMatcher(spec string) (Matcher, error) {
+
+	if m, ok := parsed.Load(spec); ok {
+		return m.(Matcher), nil
+	}
+
+	m, err := ParseMatcher(spec)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Store(spec, m)
+
+	return m, nil
+}
+
+// SetDefaultMatch sets the matcher spec of every Problem that
+// does not specify one.
+func SetDefaultMatch(problems []Problem, spec string) {
+
+	for i := range problems {
+		if problems[i].Match == "" {
+			problems[i].Match = spec
+		}
+	}
+}
+
+// newExactMatcher returns a Matcher that compares the response
+// and answer once they are stripped of formatting.
+func newExactMatcher(param string) (Matcher, error) {
+
+	if param != "" {
+		return nil, fmt.Errorf("unexpected parameter %q", param)
+	}
+
+	return MatcherFunc(func(response string, answer string) bool {
+		return normalize(response) == normalize(answer)
+	}), nil
+}
+
+// newNumericMatcher returns a Matcher that compares the response
+// and answer as numbers, e.g. "10.0" matches "10". The parameter
+// is the tolerance allowed between them.
+func newNumericMatcher(param string) (Matcher, error) {
+
+	tolerance := 1e-9
+	if param != "" {
+		var err error
+		tolerance, err = strconv.ParseFloat(param, 64)
+		if err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("invalid tolerance %q", param)
+		}
+	}
+
+	return MatcherFunc(func(response string, answer string) bool {
+
+		r, err := strconv.ParseFloat(normalize(response), 64)
+		if err != nil {
+			return false
+		}
+		a, err := strconv.ParseFloat(normalize(answer), 64)
+		if err != nil {
+			return false
+		}
+
+		return math.Abs(r-a) <= tolerance
+	}), nil
+}
+
+// regexMatcher treats the answer as a regular expression which
+// must match the whole response. The match is case-insensitive.
+// Each answer is compiled once, when it is first matched.
+type regexMatcher struct {
+	compiled sync.Map
+}
+
+// newRegexMatcher returns a regexMatcher, which takes no
+// parameter.
+func newRegexMatcher(param string) (Matcher, error) {
+
+	if param != "" {
+		return nil, fmt.Errorf("unexpected parameter %q", param)
+	}
+
+	return &regexMatcher{}, nil
+}
+
+func (m *regexMatcher) Match(response string, answer string) bool {
+
+	var re *regexp.Regexp
+	if v, ok := m.compiled.Load(answer); ok {
+		re = v.(*regexp.Regexp)
+	} else {
+		var err error
+		if re, err = compileAnswer(answer); err != nil {
+			return false
+		}
+		m.compiled.Store(answer, re)
+	}
+
+	return re.MatchString(norm.NFKC.String(strings.TrimSpace(response)))
+}
+
+func (*regexMatcher) ValidateAnswer(answer string) error {
+
+	_, err := compileAnswer(answer)
+
+	return err
+}
+
+// compileAnswer compiles a regular expression answer so that
+// it must match the whole of a response.
+func compileAnswer(answer string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)^(?:` + answer + `)$`)
+}
+
+// newFuzzyMatcher returns a Matcher that accepts a response
+// within a Levenshtein distance of the answer. The parameter is
+// the maximum distance, which defaults to 1.
+func newFuzzyMatcher(param string) (Matcher, error) {
+
+	threshold := 1
+	if param != "" {
+		var err error
+		threshold, err = strconv.Atoi(param)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold %q", param)
+		}
+	}
+
+	return MatcherFunc(func(response string, answer string) bool {
+		return levenshtein(normalize(response), normalize(answer)) <= threshold
+	}), nil
+}
+
+// levenshtein returns the minimum number of single-character
+// insertions, deletions and substitutions to change a into b.
+func levenshtein(a string, b string) int {
+
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
+
+// minInt returns the smallest of the specified integers.
+func minInt(first int, rest ...int) int {
+
+	result := first
+	for _, n := range rest {
+		if n < result {
+			result = n
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,192 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMatcher(t *testing.T) {
+
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{"", ""},
+		{"exact", ""},
+		{" Exact ", ""},
+		{"numeric", ""},
+		{"numeric: 0.5 ", ""},
+		{"numeric:0", ""},
+		{"regex", ""},
+		{"fuzzy", ""},
+		{"fuzzy:0", ""},
+		{"fuzzy:3", ""},
+
+		{"exact:1", `unexpected parameter "1"`},
+		{"regex:i", `unexpected parameter "i"`},
+		{"numeric:abc", `invalid tolerance "abc"`},
+		{"numeric:-1", `invalid tolerance "-1"`},
+		{"fuzzy:1.5", `invalid threshold "1.5"`},
+		{"fuzzy:-1", `invalid threshold "-1"`},
+		{"soundex", `unknown matcher "soundex" (expected one of exact, fuzzy, numeric, regex)`},
+		{":1", `unknown matcher ""`},
+	}
+
+	for _, test := range tests {
+		m, err := ParseMatcher(test.spec)
+		switch {
+		case test.err == "" && (err != nil || m == nil):
+			t.Errorf("%q: got error %v", test.spec, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%q: got error %v, want one containing %q", test.spec, err, test.err)
+		}
+	}
+}
+
+func TestMatchers(t *testing.T) {
+
+	tests := []struct {
+		spec     string
+		response string
+		answer   string
+		want     bool
+	}{
+		{"exact", "Paris", "paris", true},
+		{"exact", " new  york ", "New York", true},
+		{"exact", "Straße", "STRASSE", true},
+		{"exact", "café", "cafe", true},
+		{"exact", "１０", "10", true},
+		{"exact", "10.0", "10", false},
+		{"exact", "", "", true},
+		{"", "Paris", "PARIS", true},
+
+		{"numeric", "10.0", "10", true},
+		{"numeric", " 1e1 ", "10", true},
+		{"numeric", "１０", "10", true},
+		{"numeric", "10.1", "10", false},
+		{"numeric", "ten", "10", false},
+		{"numeric", "10", "ten", false},
+		{"numeric:0.5", "3.5", "3", true},
+		{"numeric:0.5", "2.5", "3", true},
+		{"numeric:0.5", "3.51", "3", false},
+		{"numeric:0", "3.0", "3", true},
+		{"numeric:0", "3.000001", "3", false},
+
+		{"regex", "colour", "colou?r", true},
+		{"regex", "COLOR", "colou?r", true},
+		{"regex", " color ", "colou?r", true},
+		{"regex", "colors", "colou?r", false},
+		{"regex", "a color", "colou?r", false},
+		{"regex", "cat", "cat|dog", true},
+		{"regex", "cat or dog", "cat|dog", false},
+		{"regex", "１２", `\d+`, true},
+		{"regex", "anything", "(unclosed", false},
+
+		{"fuzzy", "paris", "paris", true},
+		{"fuzzy", "pari", "paris", true},
+		{"fuzzy", "parsi", "paris", false},
+		{"fuzzy", "PARIS ", "paris", true},
+		{"fuzzy:0", "pari", "paris", false},
+		{"fuzzy:2", "parsi", "paris", true},
+		{"fuzzy:2", "prsi", "paris", false},
+		{"fuzzy:2", "zürich", "zurich", true},
+		{"fuzzy:1", "", "a", true},
+		{"fuzzy:1", "", "ab", false},
+	}
+
+	for _, test := range tests {
+		m, err := ParseMatcher(test.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", test.spec, err)
+		}
+		if got := m.Match(test.response, test.answer); got != test.want {
+			t.Errorf("%q: got %v for %q against %q, want %v",
+				test.spec, got, test.response, test.answer, test.want)
+		}
+	}
+}
+
+func TestRegexValidateAnswer(t *testing.T) {
+
+	m, _ := ParseMatcher("regex")
+	v, ok := m.(answerValidator)
+	if !ok {
+		t.Fatal("the regex matcher does not validate answers")
+	}
+
+	if err := v.ValidateAnswer("colou?r"); err != nil {
+		t.Errorf("ValidateAnswer of a valid expression: %v", err)
+	}
+	if err := v.ValidateAnswer("(unclosed"); err == nil {
+		t.Error("ValidateAnswer of an invalid expression: got no error")
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"paris", "parsi", 2},
+		{"über", "uber", 1},
+		{"日本", "日本語", 1},
+	}
+
+	for _, test := range tests {
+		if got := levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("levenshtein(%q, %q): got %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestCachedMatcher(t *testing.T) {
+
+	// The factory counts how often the spec is parsed.
+	calls := 0
+	RegisterMatcher("counting", func(param string) (Matcher, error) {
+		calls++
+		return &regexMatcher{}, nil
+	})
+	t.Cleanup(func() {
+		delete(matchers, "counting")
+		parsed.Delete("counting")
+	})
+
+	first, err := cachedMatcher("counting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := cachedMatcher("counting")
+	if first != second || calls != 1 {
+		t.Errorf("got %d parses of the spec, want the Matcher to be cached", calls)
+	}
+
+	// Registering a matcher drops the cached ones, which may have
+	// been replaced.
+	RegisterMatcher("other", newExactMatcher)
+	delete(matchers, "other")
+	if third, _ := cachedMatcher("counting"); third == first || calls != 2 {
+		t.Errorf("got %d parses of the spec, want the cache to be dropped", calls)
+	}
+
+	// Bad specs are not cached, and fail every time.
+	for i := 0; i < 2; i++ {
+		if _, err := cachedMatcher("counting-not"); err == nil {
+			t.Error("cachedMatcher of an unknown matcher: got no error")
+		}
+	}
+	if _, ok := parsed.Load("counting-not"); ok {
+		t.Error("the unknown matcher was cached")
+	}
+
+	p := Problem{Question: "q", Answer: "a", Match: "counting-not"}
+	if p.Check("a") {
+		t.Error("a Problem with an unknown matcher accepted its answer")
+	}
+}
@@ -0,0 +1,171 @@
+package quiz
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// imageExtensions and audioExtensions are the file extensions
+// accepted for the Image and Audio of a Problem.
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp"}
+var audioExtensions = []string{".mp3", ".ogg", ".oga", ".wav", ".m4a", ".flac"}
+
+// parseMedia validates a reference to an image or audio clip
+// with one of the specified extensions. A reference is either an
+// http(s) URL or a slash-separated path relative to the problem
+// source, which may not lead outside of its directory.
+func parseMedia(kind string, text string, exts []string) (string, error) {
+
+	ref := strings.TrimSpace(text)
+	if ref == "" {
+		return "", nil
+	}
+
+	p := ref
+	if isURL(ref) {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s URL %q", kind, ref)
+		}
+		p = u.Path
+	} else {
+		ref = path.Clean(filepath.ToSlash(ref))
+		if !fs.ValidPath(ref) || ref == "." {
+			return "", fmt.Errorf("invalid %s path %q (expected a relative path within the problem directory)", kind, text)
+		}
+		p = ref
+	}
+
+	ext := strings.ToLower(path.Ext(p))
+	for _, e := range exts {
+		if ext == e {
+			return ref, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported %s %q (expected one of %s)", kind, text, strings.Join(exts, ", "))
+}
+
+// parseCode returns a code snippet and its language. The snippet
+// may be written as a Markdown fenced code block, e.g.
+//
+//	```go
+//	fmt.Println("hi")
+//	```
+//
+// whose language is used unless one is given separately.
+func parseCode(text string, language string) (string, string, error) {
+
+	language = strings.ToLower(strings.TrimSpace(language))
+	code := strings.Trim(text, "\r\n")
+	if strings.TrimSpace(code) == "" {
+		return "", language, nil
+	}
+
+	trimmed := strings.TrimSpace(code)
+	if !strings.HasPrefix(trimmed, "```") {
+		return code, language, nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(trimmed, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[len(lines)-1]) != "```" {
+		return "", "", fmt.Errorf("unterminated code block %q", firstLine(trimmed))
+	}
+	if language == "" {
+		language = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(lines[0], "```")))
+	}
+
+	return strings.Join(lines[1:len(lines)-1], "\n"), language, nil
+}
+
+// firstLine returns the first line of the text.
+func firstLine(text string) string {
+
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+
+	return text
+}
+
+// Assets returns the paths of the Problem's media that are kept
+// alongside the problem source rather than at a URL.
+func (p Problem) Assets() []string {
+
+	var assets []string
+	for _, ref := range []string{p.Image, p.Audio} {
+		if ref != "" && !isURL(ref) {
+			assets = append(assets, ref)
+		}
+	}
+
+	return assets
+}
+
+// resolveMedia replaces the relative paths of the Problems'
+// media with the reference the resolve function returns.
+func resolveMedia(problems []Problem, resolve func(ref string) string) {
+
+	for i := range problems {
+		for _, ref := range []*string{&problems[i].Image, &problems[i].Audio} {
+			if *ref != "" && !isURL(*ref) {
+				*ref = resolve(*ref)
+			}
+		}
+	}
+}
+
+// OpenAssets returns the files that the media of Problems loaded
+// from the source refer to: the directory of a problem file, or
+// the root of a Bundle. Media of a source fetched from a URL are
+// themselves URLs, so the file system is nil unless the source
+// is a zip Bundle. Close the Closer, if any, once the files are
+// no longer needed.
+func OpenAssets(source string, cache *Cache) (fs.FS, io.Closer, error) {
+
+	p := source
+	if i := strings.LastIndex(p, "#"); i >= 0 && (isURL(p) || isBundle(p[:i])) {
+		p = p[:i]
+	}
+
+	if isURL(p) {
+		u, err := url.Parse(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !strings.EqualFold(path.Ext(u.Path), ".zip") {
+			return nil, nil, nil
+		}
+		if p, err = cache.Fetch(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !isBundle(p) {
+		return os.DirFS(filepath.Dir(p)), nil, nil
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(p), nil, nil
+	}
+
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", p, err)
+	}
+	if sub, ok := singleDir(zr); ok {
+		return sub, zr, nil
+	}
+
+	return zr, zr, nil
+}
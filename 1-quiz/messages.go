@@ -0,0 +1,221 @@
+package quiz
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func init() {
+	for locale, catalog := range catalogs {
+		if err := RegisterCatalog(locale, catalog); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// DEFAULT_LOCALE is the locale whose messages are used when no
+// catalog matches, and which the catalogs translate from.
+const DEFAULT_LOCALE = "en"
+
+// catalogs holds the translation of every message written to
+// the user, keyed by the English message, for each locale.
+var catalogs = map[string]map[string]string{
+	"es": {
+		"Time's up!\n":                     "¡Se acabó el tiempo!\n",
+		"%d...\n":                          "%d...\n",
+		"Paused. Press enter to resume.\n": "En pausa. Pulsa enter para continuar.\n",
+		"choose one letter":                "elige una letra",
+		"true or false":                    "verdadero o falso",
+		"True":                             "Verdadero",
+		"False":                            "Falso",
+		"choose all that apply, e.g. a,c":  "elige todas las correctas, p. ej. a,c",
+
+		"You are about to take a quiz.\n":                                         "Estás a punto de hacer un cuestionario.\n",
+		"The timer is set to %d seconds.\n":                                       "El temporizador está en %d segundos.\n",
+		"Shuffling is off.\n":                                                     "El orden es fijo.\n",
+		"Shuffling is on.\n":                                                      "El orden es aleatorio.\n",
+		"Shuffling is on (within categories).\n":                                  "El orden es aleatorio (dentro de cada categoría).\n",
+		"You are about to resume a quiz with %d questions left.\n":                "Estás a punto de reanudar un cuestionario con %d preguntas pendientes.\n",
+		"The timer has %d seconds left.\n":                                        "Quedan %d segundos en el temporizador.\n",
+		"Enter %s to pause the timer.\n":                                          "Escribe %s para pausar el temporizador.\n",
+		"Please press enter to begin.\n":                                          "Pulsa enter para empezar.\n",
+		"Text detected; terminating quiz.\n":                                      "Se detectó texto; terminando el cuestionario.\n",
+		"Quiz interrupted.\n":                                                     "Cuestionario interrumpido.\n",
+		"You scored %g out of %g points (%d of %d correct).\n":                    "Has obtenido %g de %g puntos (%d de %d correctas).\n",
+		"Your estimated skill rating is %.1f (1 is easy, 2 medium and 3 hard).\n": "Tu nivel estimado es %.1f (1 es fácil, 2 medio y 3 difícil).\n",
+		"Replay this quiz with -seed %s.\n":                                       "Repite este cuestionario con -seed %s.\n",
+		"Resume this quiz with -resume -session %s.\n":                            "Reanuda este cuestionario con -resume -session %s.\n",
+		"No questions are due for review until %s.\n":                             "No hay preguntas pendientes de repaso hasta el %s.\n",
+		"The next review is due %s.\n":                                            "El próximo repaso es el %s.\n",
+		"  %s: %g out of %g points (%d of %d correct)\n":                          "  %s: %g de %g puntos (%d de %d correctas)\n",
+		"Uncategorized":                                                           "Sin categoría",
+		"%d answers were flagged as given too fast or pasted in.\n":               "%d respuestas se marcaron por ser demasiado rápidas o pegadas.\n",
+
+		"Question %d of %d":             "Pregunta %d de %d",
+		"Score: %g/%g":                  "Puntuación: %g/%g",
+		"Correct!":                      "¡Correcto!",
+		"Incorrect. The answer was %q.": "Incorrecto. La respuesta era %q.",
+		"Time's up! The answer was %q.": "¡Se acabó el tiempo! La respuesta era %q.",
+		"No time limit":                 "Sin límite de tiempo",
+		"%ds":                           "%d s",
+		"Image: %s":                     "Imagen: %s",
+		"Audio: %s":                     "Audio: %s",
+	},
+	"fr": {
+		"Time's up!\n":                     "Temps écoulé !\n",
+		"%d...\n":                          "%d...\n",
+		"Paused. Press enter to resume.\n": "En pause. Appuyez sur entrée pour reprendre.\n",
+		"choose one letter":                "choisissez une lettre",
+		"true or false":                    "vrai ou faux",
+		"True":                             "Vrai",
+		"False":                            "Faux",
+		"choose all that apply, e.g. a,c":  "choisissez toutes les bonnes réponses, p. ex. a,c",
+
+		"You are about to take a quiz.\n":                                         "Vous allez commencer un quiz.\n",
+		"The timer is set to %d seconds.\n":                                       "Le minuteur est réglé sur %d secondes.\n",
+		"Shuffling is off.\n":                                                     "Les questions ne sont pas mélangées.\n",
+		"Shuffling is on.\n":                                                      "Les questions sont mélangées.\n",
+		"Shuffling is on (within categories).\n":                                  "Les questions sont mélangées (au sein de chaque catégorie).\n",
+		"You are about to resume a quiz with %d questions left.\n":                "Vous allez reprendre un quiz dont il reste %d questions.\n",
+		"The timer has %d seconds left.\n":                                        "Il reste %d secondes au minuteur.\n",
+		"Enter %s to pause the timer.\n":                                          "Saisissez %s pour mettre le minuteur en pause.\n",
+		"Please press enter to begin.\n":                                          "Appuyez sur entrée pour commencer.\n",
+		"Text detected; terminating quiz.\n":                                      "Texte détecté ; fin du quiz.\n",
+		"Quiz interrupted.\n":                                                     "Quiz interrompu.\n",
+		"You scored %g out of %g points (%d of %d correct).\n":                    "Vous avez obtenu %g points sur %g (%d bonnes réponses sur %d).\n",
+		"Your estimated skill rating is %.1f (1 is easy, 2 medium and 3 hard).\n": "Votre niveau estimé est de %.1f (1 facile, 2 moyen et 3 difficile).\n",
+		"Replay this quiz with -seed %s.\n":                                       "Rejouez ce quiz avec -seed %s.\n",
+		"Resume this quiz with -resume -session %s.\n":                            "Reprenez ce quiz avec -resume -session %s.\n",
+		"No questions are due for review until %s.\n":                             "Aucune question à réviser avant le %s.\n",
+		"The next review is due %s.\n":                                            "La prochaine révision est prévue le %s.\n",
+		"  %s: %g out of %g points (%d of %d correct)\n":                          "  %s : %g points sur %g (%d bonnes réponses sur %d)\n",
+		"Uncategorized":                                                           "Sans catégorie",
+		"%d answers were flagged as given too fast or pasted in.\n":               "%d réponses ont été signalées comme trop rapides ou collées.\n",
+
+		"Question %d of %d":             "Question %d sur %d",
+		"Score: %g/%g":                  "Score : %g/%g",
+		"Correct!":                      "Correct !",
+		"Incorrect. The answer was %q.": "Incorrect. La réponse était %q.",
+		"Time's up! The answer was %q.": "Temps écoulé ! La réponse était %q.",
+		"No time limit":                 "Pas de limite de temps",
+		"%ds":                           "%d s",
+		"Image: %s":                     "Image : %s",
+		"Audio: %s":                     "Audio : %s",
+	},
+	"de": {
+		"Time's up!\n":                     "Die Zeit ist um!\n",
+		"%d...\n":                          "%d...\n",
+		"Paused. Press enter to resume.\n": "Pausiert. Drücke Enter zum Fortsetzen.\n",
+		"choose one letter":                "wähle einen Buchstaben",
+		"true or false":                    "wahr oder falsch",
+		"True":                             "Wahr",
+		"False":                            "Falsch",
+		"choose all that apply, e.g. a,c":  "wähle alle zutreffenden, z. B. a,c",
+
+		"You are about to take a quiz.\n":                                         "Gleich beginnt ein Quiz.\n",
+		"The timer is set to %d seconds.\n":                                       "Der Timer steht auf %d Sekunden.\n",
+		"Shuffling is off.\n":                                                     "Die Reihenfolge ist fest.\n",
+		"Shuffling is on.\n":                                                      "Die Reihenfolge ist zufällig.\n",
+		"Shuffling is on (within categories).\n":                                  "Die Reihenfolge ist zufällig (innerhalb jeder Kategorie).\n",
+		"You are about to resume a quiz with %d questions left.\n":                "Gleich wird ein Quiz mit %d verbleibenden Fragen fortgesetzt.\n",
+		"The timer has %d seconds left.\n":                                        "Der Timer hat noch %d Sekunden.\n",
+		"Enter %s to pause the timer.\n":                                          "Gib %s ein, um den Timer anzuhalten.\n",
+		"Please press enter to begin.\n":                                          "Drücke Enter, um zu beginnen.\n",
+		"Text detected; terminating quiz.\n":                                      "Text erkannt; das Quiz wird beendet.\n",
+		"Quiz interrupted.\n":                                                     "Quiz unterbrochen.\n",
+		"You scored %g out of %g points (%d of %d correct).\n":                    "Du hast %g von %g Punkten erreicht (%d von %d richtig).\n",
+		"Your estimated skill rating is %.1f (1 is easy, 2 medium and 3 hard).\n": "Dein geschätztes Niveau ist %.1f (1 leicht, 2 mittel und 3 schwer).\n",
+		"Replay this quiz with -seed %s.\n":                                       "Wiederhole dieses Quiz mit -seed %s.\n",
+		"Resume this quiz with -resume -session %s.\n":                            "Setze dieses Quiz mit -resume -session %s fort.\n",
+		"No questions are due for review until %s.\n":                             "Bis %s sind keine Fragen zur Wiederholung fällig.\n",
+		"The next review is due %s.\n":                                            "Die nächste Wiederholung ist am %s fällig.\n",
+		"  %s: %g out of %g points (%d of %d correct)\n":                          "  %s: %g von %g Punkten (%d von %d richtig)\n",
+		"Uncategorized":                                                           "Ohne Kategorie",
+		"%d answers were flagged as given too fast or pasted in.\n":               "%d Antworten wurden als zu schnell oder eingefügt markiert.\n",
+
+		"Question %d of %d":             "Frage %d von %d",
+		"Score: %g/%g":                  "Punkte: %g/%g",
+		"Correct!":                      "Richtig!",
+		"Incorrect. The answer was %q.": "Falsch. Die Antwort war %q.",
+		"Time's up! The answer was %q.": "Die Zeit ist um! Die Antwort war %q.",
+		"No time limit":                 "Kein Zeitlimit",
+		"%ds":                           "%d s",
+		"Image: %s":                     "Bild: %s",
+		"Audio: %s":                     "Audio: %s",
+	},
+}
+
+// RegisterCatalog adds the translations of messages, keyed by
+// the English message, to the catalog of the specified locale,
+// e.g. "pt-BR".
+func RegisterCatalog(locale string, messages map[string]string) error {
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return err
+	}
+
+	for key, msg := range messages {
+		if err := message.SetString(tag, key, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Locales returns the locales that have a catalog of messages.
+func Locales() []string {
+
+	var locales []string
+	for _, tag := range message.DefaultCatalog.Languages() {
+		locales = append(locales, tag.String())
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// NewPrinter returns a Printer of messages in the catalog best
+// matching the locale, e.g. "fr_CA.UTF-8" is printed in French.
+// Messages without a translation are printed in English.
+func NewPrinter(locale string) *message.Printer {
+
+	// POSIX locales name the encoding after a dot, and separate
+	// the region with an underscore.
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ReplaceAll(locale, "_", "-")
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.MustParse(DEFAULT_LOCALE)
+	}
+
+	supported := append(
+		[]language.Tag{language.MustParse(DEFAULT_LOCALE)},
+		message.DefaultCatalog.Languages()...,
+	)
+	_, i, _ := language.NewMatcher(supported).Match(tag)
+
+	return message.NewPrinter(supported[i])
+}
+
+// DetectLocale returns the user's locale as set by the LC_ALL,
+// LC_MESSAGES or LANG environment variables, in that order of
+// precedence, or DEFAULT_LOCALE if none is set.
+func DetectLocale() string {
+
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" && locale != "C" && locale != "POSIX" {
+			return locale
+		}
+	}
+
+	return DEFAULT_LOCALE
+}
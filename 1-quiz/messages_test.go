@@ -0,0 +1,63 @@
+package quiz
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// verbs matches the formatting verbs of a message, e.g. "%.1f".
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsComplete(t *testing.T) {
+
+	keys := map[string]bool{}
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			keys[key] = true
+		}
+	}
+
+	for locale, catalog := range catalogs {
+		for key := range keys {
+			msg, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %q", locale, key)
+				continue
+			}
+
+			// A translation takes the same arguments in the same
+			// order, and ends a line where its message does.
+			want, got := verbs.FindAllString(key, -1), verbs.FindAllString(msg, -1)
+			if strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("%s: %q has the verbs %v, want %v", locale, msg, got, want)
+			}
+			if strings.HasSuffix(key, "\n") != strings.HasSuffix(msg, "\n") {
+				t.Errorf("%s: %q does not end its line as %q does", locale, msg, key)
+			}
+		}
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"en", "Correct!"},
+		{"fr", "Correct !"},
+		{"fr_CA.UTF-8", "Correct !"},
+		{"de_DE@euro", "Richtig!"},
+		{"es-MX", "¡Correcto!"},
+		{"ja", "Correct!"},
+		{"not a locale", "Correct!"},
+		{"", "Correct!"},
+	}
+
+	for _, test := range tests {
+		if got := NewPrinter(test.locale).Sprintf("Correct!"); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.locale, got, test.want)
+		}
+	}
+}
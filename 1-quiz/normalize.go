@@ -0,0 +1,47 @@
+package quiz
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalize strips an answer of formatting to ensure a valid
+// comparison, so that answers written in any script compare
+// equal however they were typed:
+//
+//   - compatibility characters are replaced (NFKC), e.g. the
+//     full-width digit "１" becomes "1";
+//   - case is folded, e.g. "Straße" becomes "strasse";
+//   - diacritics are removed, e.g. "café" becomes "cafe";
+//   - whitespace of any kind is removed.
+func normalize(s string) string {
+
+	s = norm.NFKC.String(s)
+	s = cases.Fold().String(s)
+	s = removeDiacritics(s)
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// removeDiacritics strips the combining marks from the string,
+// leaving it composed (NFC).
+func removeDiacritics(s string) string {
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+
+	return result
+}
@@ -0,0 +1,46 @@
+package quiz
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unchanged", "paris", "paris"},
+		{"empty", "", ""},
+
+		// Compatibility characters are replaced (NFKC).
+		{"full-width digits", "１０", "10"},
+		{"full-width letters", "ＰＡＲＩＳ", "paris"},
+		{"ligature", "ﬁsh", "fish"},
+		{"superscript", "x²", "x2"},
+		{"fraction", "½", "1⁄2"},
+
+		// Case is folded, not just lowered.
+		{"upper case", "PARIS", "paris"},
+		{"sharp s", "Straße", "strasse"},
+		{"final sigma", "ΟΔΟΣ", "οδοσ"},
+
+		// Diacritics are removed, whether composed or combining.
+		{"composed accent", "café", "cafe"},
+		{"combining accent", "cafe\u0301", "cafe"},
+		{"umlaut", "Zürich", "zurich"},
+		{"cedilla", "Ça", "ca"},
+		{"other scripts", "東京", "東京"},
+
+		// Whitespace of any kind is removed.
+		{"spaces", " new  york ", "newyork"},
+		{"tabs and newlines", "new\tyork\r\n", "newyork"},
+		{"no-break space", "new\u00a0york", "newyork"},
+		{"ideographic space", "new\u3000york", "newyork"},
+	}
+
+	for _, test := range tests {
+		if got := normalize(test.in); got != test.want {
+			t.Errorf("%s: normalize(%q) = %q, want %q", test.name, test.in, got, test.want)
+		}
+	}
+}
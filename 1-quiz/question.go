@@ -0,0 +1,241 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// QuestionType determines how a Problem is presented to the user
+// and how their response is graded.
+type QuestionType string
+
+const (
+	// TypeText is a free-text question graded by its Matcher.
+	TypeText QuestionType = "text"
+
+	// TypeChoice is a multiple-choice question with lettered
+	// options, exactly one of which is the Answer.
+	TypeChoice QuestionType = "choice"
+
+	// TypeTrueFalse is a question answered with true or false.
+	TypeTrueFalse QuestionType = "truefalse"
+
+	// TypeMultiSelect is a question with lettered options, every
+	// accepted answer of which must be selected. Partial credit
+	// is given for a partially correct selection.
+	TypeMultiSelect QuestionType = "multi"
+)
+
+// questionTypes maps each name a QuestionType may be written as
+// in a problem source to that QuestionType.
+var questionTypes = map[string]QuestionType{
+	"":                TypeText,
+	"text":            TypeText,
+	"choice":          TypeChoice,
+	"multiple-choice": TypeChoice,
+	"truefalse":       TypeTrueFalse,
+	"true/false":      TypeTrueFalse,
+	"tf":              TypeTrueFalse,
+	"multi":           TypeMultiSelect,
+	"multi-select":    TypeMultiSelect,
+}
+
+// ParseQuestionType returns the QuestionType with the specified
+// name. An empty name is TypeText.
+func ParseQuestionType(name string) (QuestionType, error) {
+
+	if t, ok := questionTypes[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return t, nil
+	}
+
+	return "", fmt.Errorf("unsupported question type %q", name)
+}
+
+// hasOptions reports whether questions of the type are answered
+// by choosing from lettered options.
+func (t QuestionType) hasOptions() bool {
+	return t == TypeChoice || t == TypeMultiSelect || t == TypeTrueFalse
+}
+
+// Prompt is a Problem as it is presented to the user, with its
+// options in the order they are displayed.
+type Prompt struct {
+	Problem Problem
+	Options []string
+}
+
+// Prompt returns the Problem as it is presented to the user.
+// The options of multiple-choice and multi-select questions are
+// shuffled using the specified source.
+func (p Problem) Prompt(rng *rand.Rand) Prompt {
+
+	prompt := Prompt{Problem: p}
+
+	switch p.Type {
+
+	case TypeTrueFalse:
+		prompt.Options = []string{"True", "False"}
+
+	case TypeChoice, TypeMultiSelect:
+		prompt.Options = append([]string(nil), p.Options...)
+		rng.Shuffle(
+			len(prompt.Options),
+			func(i int, j int) {
+				prompt.Options[i], prompt.Options[j] = prompt.Options[j], prompt.Options[i]
+			},
+		)
+
+	}
+
+	return prompt
+}
+
+// Letter returns the letter the i-th option is displayed with.
+func Letter(i int) string {
+	return string(rune('a' + i))
+}
+
+// Hint returns a description of how the prompt is answered, or
+// an empty string for free-text questions.
+func (p Prompt) Hint() string {
+
+	switch p.Problem.Type {
+	case TypeChoice:
+		return "choose one letter"
+	case TypeTrueFalse:
+		return "true or false"
+	case TypeMultiSelect:
+		return "choose all that apply, e.g. a,c"
+	}
+
+	return ""
+}
+
+// Grade returns the fraction of the Problem's points earned by
+// the response, from 0 to 1.
+func (p Prompt) Grade(response string) float64 {
+
+	switch p.Problem.Type {
+
+	case TypeChoice:
+		if option, ok := p.option(strings.TrimSpace(response)); ok {
+			response = option
+		}
+
+	case TypeTrueFalse:
+		return p.gradeTrueFalse(response)
+
+	case TypeMultiSelect:
+		return p.gradeMultiSelect(response)
+
+	}
+
+	if p.Problem.Check(response) {
+		return 1
+	}
+
+	return 0
+}
+
+// option returns the option displayed with the specified
+// letter.
+func (p Prompt) option(letter string) (string, bool) {
+
+	letter = normalize(letter)
+	if len(letter) != 1 || letter[0] < 'a' || int(letter[0]-'a') >= len(p.Options) {
+		return "", false
+	}
+
+	return p.Options[letter[0]-'a'], true
+}
+
+// gradeTrueFalse accepts the response by letter, e.g. "a", by
+// word, e.g. "true" or "vrai", or by initial, e.g. "t".
+func (p Prompt) gradeTrueFalse(response string) float64 {
+
+	response = normalize(response)
+	if option, ok := p.option(response); ok {
+		response = normalize(option)
+	}
+
+	given, ok := parseBool(response)
+	if !ok {
+		return 0
+	}
+	expected, _ := parseBool(normalize(p.Problem.Answer))
+	if given != expected {
+		return 0
+	}
+
+	return 1
+}
+
+// gradeMultiSelect gives credit for each accepted answer that
+// was selected, less each option selected in error.
+func (p Prompt) gradeMultiSelect(response string) float64 {
+
+	accepted := map[string]bool{}
+	for _, answer := range p.Problem.Accepted() {
+		accepted[normalize(answer)] = true
+	}
+
+	selected := map[string]bool{}
+	for _, letter := range splitSelection(response) {
+		option, ok := p.option(letter)
+		if !ok {
+			return 0
+		}
+		selected[normalize(option)] = true
+	}
+
+	credit := 0
+	for option := range selected {
+		if accepted[option] {
+			credit++
+		} else {
+			credit--
+		}
+	}
+	if credit <= 0 {
+		return 0
+	}
+
+	return float64(credit) / float64(len(accepted))
+}
+
+// splitSelection splits a multi-select response into letters,
+// which may be separated by commas or spaces, e.g. "a,c", or
+// written together, e.g. "ac".
+func splitSelection(response string) []string {
+
+	var letters []string
+	fields := strings.FieldsFunc(strings.ToLower(norm.NFKC.String(response)), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	})
+	for _, field := range fields {
+		for _, r := range field {
+			letters = append(letters, string(r))
+		}
+	}
+
+	return letters
+}
+
+// parseBool parses a normalized true/false answer, which may
+// also be written in Spanish, French or German.
+func parseBool(s string) (bool, bool) {
+
+	switch s {
+	case "true", "t", "yes", "y",
+		"verdadero", "si", "vrai", "oui", "wahr", "ja":
+		return true, true
+	case "false", "f", "no", "n",
+		"falso", "faux", "non", "falsch", "nein":
+		return false, true
+	}
+
+	return false, false
+}
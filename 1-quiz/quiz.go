@@ -0,0 +1,339 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrAborted is returned by Run when the user enters text
+// instead of pressing enter to begin the quiz.
+var ErrAborted = errors.New("text detected; terminating quiz")
+
+// Problem is a single quiz question and the answer expected
+// from the user.
+type Problem struct {
+	Question string
+	Answer   string
+
+	// Alternatives are further answers that are also accepted.
+	// For multi-select questions, every accepted answer is an
+	// option that must be selected.
+	Alternatives []string
+
+	// Type determines how the question is presented and graded.
+	// An empty Type is TypeText.
+	Type QuestionType
+
+	// Options are the choices given for multiple-choice and
+	// multi-select questions.
+	Options []string
+
+	// Match is the spec of the Matcher that compares responses
+	// with the accepted answers. See ParseMatcher.
+	Match string
+
+	// TimeLimit is the time given to answer the question. A zero
+	// TimeLimit leaves the question limited only by the Quiz.
+	TimeLimit time.Duration
+
+	// Points is the score given for a correct answer. Zero
+	// Points are counted as 1.
+	Points float64
+
+	// Category groups related questions, e.g. "arithmetic".
+	Category string
+
+	// Tags label the question with further topics it covers, e.g.
+	// "fractions". See FilterTags.
+	Tags []string
+
+	// Image and Audio refer to media shown with the question,
+	// either by URL or by a path relative to the problem source.
+	Image string
+	Audio string
+
+	// Code is a snippet of source code shown with the question,
+	// highlighted as the Language, e.g. "go".
+	Code     string
+	Language string
+
+	// Difficulty rates how hard the question is, from
+	// DifficultyEasy upwards. Zero is unrated. See Adaptive.
+	Difficulty int
+
+	// Line is the line of the problem source the Problem was
+	// read from, or zero if it is unknown.
+	Line int
+}
+
+// Value returns the score given for a correct answer.
+func (p Problem) Value() float64 {
+
+	if p.Points == 0 {
+		return 1
+	}
+
+	return p.Points
+}
+
+// Accepted returns every answer accepted for the Problem.
+func (p Problem) Accepted() []string {
+	return append([]string{p.Answer}, p.Alternatives...)
+}
+
+// Check reports whether the response matches any accepted
+// answer using the Problem's Matcher.
+func (p Problem) Check(response string) bool {
+
+	matcher, err := cachedMatcher(p.Match)
+	if err != nil {
+		return false
+	}
+
+	for _, answer := range p.Accepted() {
+		if matcher.Match(response, answer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Quiz is an ordered set of Problems that are given to the
+// user under a time limit.
+type Quiz struct {
+	Problems  []Problem
+	TimeLimit time.Duration
+
+	// Seed seeds the source used to shuffle the options of each
+	// question as it is asked. A zero Seed is chosen from the
+	// current time.
+	Seed int64
+
+	// Policy chooses which Problem is asked next. A nil Policy
+	// asks the Problems in order.
+	Policy Policy
+
+	// Questions is how many of the Problems are asked, e.g. when
+	// the Policy draws from a larger pool. Zero Questions asks
+	// every Problem.
+	Questions int
+
+	// Locale selects the catalog of the messages written while
+	// the quiz is given, e.g. "fr". See NewPrinter.
+	Locale string
+
+	// Proctor, if set, flags responses that were given too fast
+	// or pasted in.
+	Proctor *Proctor
+
+	// Key, if set, keys the hash chain each answer is linked into
+	// as it is recorded, so that the answers of an Attempt cannot
+	// be changed without it. See Attempt.Verify.
+	Key []byte
+}
+
+// Total returns how many questions are asked: the Questions, if
+// fewer than the Problems, and otherwise every Problem.
+func (q *Quiz) Total() int {
+	return questionCount(len(q.Problems), q.Questions)
+}
+
+// New reads CSV data from the specified reader and returns a
+// Quiz of its Problems.
+//
+// See Load to read Problems in other formats.
+func New(r io.Reader) (*Quiz, error) {
+
+	problems, err := LoadCSV("", r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quiz{Problems: problems}, nil
+}
+
+// countdownFrom is the time remaining on a question when the
+// countdown to its time limit begins.
+const countdownFrom = 5 * time.Second
+
+// PAUSE_COMMAND is entered in place of an answer to pause the
+// quiz, stopping the clock until enter is pressed.
+const PAUSE_COMMAND = ":pause"
+
+// Run waits for the user to press enter and then gives the
+// quiz, reading answers from in and writing questions to out.
+//
+// See Session.Play for how the quiz is given.
+func (q *Quiz) Run(in io.Reader, out io.Writer, clock Clock) (Result, error) {
+	return q.RunContext(context.Background(), in, out, clock)
+}
+
+// RunContext is Run with a context. If the context is cancelled
+// the quiz ends with the context's error, along with the Result
+// so far.
+func (q *Quiz) RunContext(ctx context.Context, in io.Reader, out io.Writer, clock Clock) (Result, error) {
+
+	input := NewClockInput(in, clock)
+	defer input.Close()
+
+	if err := input.WaitForEnter(ctx); err != nil {
+		return NewResult(q.Problems), err
+	}
+
+	session := q.Start(clock)
+	err := session.Play(ctx, input, NewLineView(out, NewPrinter(q.Locale), false))
+
+	return session.Result(), err
+}
+
+// Play gives the rest of the Session, reading answers from the
+// input and presenting the quiz in the view.
+//
+// Each question is displayed in turn, along with any lettered
+// options, and the user input is graded against the 'answer'.
+// The points earned are added to the score and the quiz
+// continues.
+//
+// A question with a TimeLimit counts down its final seconds
+// and is marked incorrect once the limit is exceeded, after
+// which the next question is asked. Entering PAUSE_COMMAND
+// stops the clock until enter is pressed.
+//
+// If the Quiz has a Proctor, a response that was pasted in or
+// entered before its question was shown is flagged. Responses
+// are timed by the Session's clock, which should also time the
+// input (see NewClockInput).
+//
+// The quiz is terminated once the Quiz TimeLimit, as measured
+// by the clock, is exceeded or the input is exhausted. A zero
+// TimeLimit never expires. If the context is cancelled first,
+// Play returns its error and the Session is left unfinished so
+// that it may be saved and resumed.
+func (s *Session) Play(ctx context.Context, input *Input, view View) error {
+
+	refresher, _ := view.(Refresher)
+
+	for {
+
+		prompt, ok := s.Next()
+		if !ok {
+			return nil
+		}
+		view.Ask(s, prompt)
+		shown := s.clock.Now()
+
+	question:
+		for {
+
+			var timeout, questionTimeout, countdown, refresh <-chan time.Time
+			var remaining time.Duration
+			now := s.clock.Now()
+			if deadline := s.Deadline(); !deadline.IsZero() {
+				timeout = s.clock.After(deadline.Sub(now))
+			}
+			if deadline := s.QuestionDeadline(); !deadline.IsZero() {
+				left := deadline.Sub(now)
+				questionTimeout = s.clock.After(left)
+				remaining = countdownFrom
+				if remaining >= left {
+					remaining = (left - 1).Truncate(time.Second)
+				}
+				if remaining > 0 {
+					countdown = s.clock.After(left - remaining)
+				}
+			}
+			if refresher != nil {
+				refresh = s.clock.After(REFRESH_INTERVAL)
+			}
+
+			if input.next() == nil {
+				// The input has already ended, e.g. while paused.
+				s.End()
+				return nil
+			}
+
+			for {
+				select {
+
+				case <-ctx.Done():
+					return ctx.Err()
+
+				case r := <-input.next():
+					text, err := input.accept(r)
+					if err == io.EOF {
+						s.End()
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					if strings.TrimSpace(text) == PAUSE_COMMAND {
+						if err := s.waitPaused(ctx, input, view); err != nil {
+							return err
+						}
+						view.Ask(s, prompt)
+						shown = s.clock.Now()
+						continue question
+					}
+					var flags []Flag
+					text, pasted := stripPaste(text)
+					if s.quiz.Proctor != nil && (pasted || r.read.Before(shown)) {
+						flags = append(flags, FlagPasted)
+					}
+					if answer, err := s.submit(text, flags); err == nil {
+						view.Answered(s, answer)
+					}
+					break question
+
+				case <-countdown:
+					view.Countdown(s, int(remaining/time.Second))
+					remaining -= time.Second
+					countdown = nil
+					if remaining > 0 {
+						countdown = s.clock.After(time.Second)
+					}
+
+				case <-refresh:
+					refresher.Refresh(s)
+					refresh = s.clock.After(REFRESH_INTERVAL)
+
+				case <-questionTimeout:
+					view.TimeUp(s)
+					s.TimeOut()
+					if answer, err := s.Submit(""); err == nil {
+						view.Answered(s, answer)
+					}
+					break question
+
+				case <-timeout:
+					view.TimeUp(s)
+					s.End()
+					return nil
+
+				}
+			}
+		}
+
+	}
+}
+
+// waitPaused stops the Session's clock until the user presses
+// enter.
+func (s *Session) waitPaused(ctx context.Context, input *Input, view View) error {
+
+	s.Pause()
+	view.Paused(s)
+
+	_, err := input.ReadLine(ctx)
+	s.Resume()
+	if err == io.EOF {
+		s.End()
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,336 @@
+package quiz
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when it is advanced,
+// firing the channels of After whose durations have elapsed.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, fakeTimer{c.now.Add(d), ch})
+
+	return ch
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+// waitTimers waits until at least n channels of After are pending,
+// i.e. until the code under test is waiting on the clock.
+func (c *fakeClock) waitTimers(t *testing.T, n int) {
+
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		pending := len(c.timers)
+		c.mu.Unlock()
+		if pending >= n {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %d timers", n)
+}
+
+// syncBuffer is a bytes.Buffer that may be read while a quiz is
+// writing to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// waitFor waits until the text has been written.
+func (b *syncBuffer) waitFor(t *testing.T, text string) {
+
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		if strings.Contains(b.String(), text) {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %q in output %q", text, b.String())
+}
+
+func newTestQuiz(t *testing.T, csv string) *Quiz {
+
+	t.Helper()
+	q, err := New(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return q
+}
+
+func TestRunGradesAnswers(t *testing.T) {
+
+	q := newTestQuiz(t, "5+5,10\n1+1,2\n\"what 2+2, sir?\",4\n")
+	in := strings.NewReader("\n10\n3\n 4 \n")
+	var out bytes.Buffer
+
+	result, err := q.Run(in, &out, newFakeClock())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if result.Correct != 2 || result.Total != 3 {
+		t.Errorf("got %d/%d correct, want 2/3", result.Correct, result.Total)
+	}
+	want := []bool{true, false, true}
+	if len(result.Answers) != len(want) {
+		t.Fatalf("got %d answers, want %d", len(result.Answers), len(want))
+	}
+	for i, answer := range result.Answers {
+		if answer.Correct != want[i] {
+			t.Errorf("answer %d (%q to %q): correct = %v, want %v",
+				i+1, answer.Response, answer.Problem.Question, answer.Correct, want[i])
+		}
+	}
+
+	for _, question := range []string{"1. 5+5?", "2. 1+1?", "3. what 2+2, sir?"} {
+		if !strings.Contains(out.String(), question) {
+			t.Errorf("output %q does not ask %q", out.String(), question)
+		}
+	}
+}
+
+func TestRunEndsWithInput(t *testing.T) {
+
+	// The last answer counts whether or not it ends its line.
+	for _, input := range []string{"\n10\n", "\n10", "\r\n10\r\n"} {
+		q := newTestQuiz(t, "5+5,10\n1+1,2\n")
+		var out bytes.Buffer
+
+		result, err := q.Run(strings.NewReader(input), &out, newFakeClock())
+		if err != nil {
+			t.Fatalf("%q: Run: %v", input, err)
+		}
+
+		if result.Correct != 1 || result.Total != 2 || len(result.Answers) != 1 {
+			t.Errorf("%q: got %d/%d correct with %d answers, want 1/2 with 1",
+				input, result.Correct, result.Total, len(result.Answers))
+		}
+	}
+}
+
+func TestRunAbortsWithoutEnter(t *testing.T) {
+
+	q := newTestQuiz(t, "5+5,10\n")
+	var out bytes.Buffer
+
+	_, err := q.Run(strings.NewReader("10\n"), &out, newFakeClock())
+	if !errors.Is(err, ErrAborted) {
+		t.Errorf("got error %v, want %v", err, ErrAborted)
+	}
+	if out.Len() != 0 {
+		t.Errorf("got output %q before the quiz started", out.String())
+	}
+}
+
+func TestRunTimeLimit(t *testing.T) {
+
+	q := newTestQuiz(t, "5+5,10\n1+1,2\n8+3,11\n")
+	q.TimeLimit = 30 * time.Second
+	clock := newFakeClock()
+
+	r, w := io.Pipe()
+	defer w.Close()
+	var out bytes.Buffer
+
+	type run struct {
+		result Result
+		err    error
+	}
+	done := make(chan run, 1)
+	go func() {
+		result, err := q.Run(r, &out, clock)
+		done <- run{result, err}
+	}()
+
+	io.WriteString(w, "\n10\n")
+
+	// Wait for the second question to be asked, and let the time
+	// limit pass before it is answered.
+	clock.waitTimers(t, 2)
+	clock.Advance(q.TimeLimit)
+
+	var got run
+	select {
+	case got = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the quiz did not end when its time limit passed")
+	}
+
+	if got.err != nil {
+		t.Fatalf("Run: %v", got.err)
+	}
+	if got.result.Correct != 1 || got.result.Total != 3 || len(got.result.Answers) != 1 {
+		t.Errorf("got %d/%d correct with %d answers, want 1/3 with 1",
+			got.result.Correct, got.result.Total, len(got.result.Answers))
+	}
+	if !strings.Contains(out.String(), "Time's up!") {
+		t.Errorf("output %q does not say the time is up", out.String())
+	}
+}
+
+func TestRunQuestionTimeLimit(t *testing.T) {
+
+	q := newTestQuiz(t, "5+5,10\n1+1,2\n")
+	q.Problems[0].TimeLimit = 10 * time.Second
+	clock := newFakeClock()
+
+	r, w := io.Pipe()
+	defer w.Close()
+	var out syncBuffer
+
+	done := make(chan Result, 1)
+	go func() {
+		result, _ := q.Run(r, &out, clock)
+		done <- result
+	}()
+
+	io.WriteString(w, "\n")
+
+	// The first question waits on its time limit and on the
+	// countdown to it.
+	clock.waitTimers(t, 2)
+	clock.Advance(10 * time.Second)
+	out.waitFor(t, "2. 1+1?")
+	io.WriteString(w, "2\n")
+	w.Close()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the quiz did not end with its input")
+	}
+
+	if len(result.Answers) != 2 {
+		t.Fatalf("got %d answers, want 2", len(result.Answers))
+	}
+	if first := result.Answers[0]; !first.TimedOut || first.Correct {
+		t.Errorf("first answer: timed out = %v, correct = %v, want true, false",
+			first.TimedOut, first.Correct)
+	}
+	if second := result.Answers[1]; !second.Correct {
+		t.Errorf("second answer %q is not correct", second.Response)
+	}
+}
+
+func TestNewReportsMalformedRows(t *testing.T) {
+
+	csv := "5+5,10\n" +
+		"1+1\n" +
+		"a \"quoted\" question,2\n" +
+		"8+3,11\n" +
+		"2+2\n"
+
+	_, err := New(strings.NewReader(csv))
+
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got error %v, want ParseErrors", err)
+	}
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Line)
+	}
+	if want := []int{2, 3, 5}; !equalInts(lines, want) {
+		t.Errorf("got errors on lines %v, want %v:\n%v", lines, want, err)
+	}
+}
+
+func TestLoadCSVQuotedComma(t *testing.T) {
+
+	problems, err := LoadCSV("test.csv", strings.NewReader("\"what 2+2, sir?\",4\n"))
+	if err != nil {
+		t.Fatalf("LoadCSV: %v", err)
+	}
+
+	if len(problems) != 1 || problems[0].Question != "what 2+2, sir?" || problems[0].Answer != "4" {
+		t.Errorf("got %+v, want the question \"what 2+2, sir?\" answered by 4", problems)
+	}
+}
+
+func equalInts(a, b []int) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
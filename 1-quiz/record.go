@@ -0,0 +1,468 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// record is the format-independent form of a problem as it is
+// written in a problem source. Structured formats unmarshal
+// into it directly while tabular formats fill it in column by
+// column.
+type record struct {
+	Question  scalar `json:"question" yaml:"question"`
+	Answer    scalar `json:"answer" yaml:"answer"`
+	TimeLimit scalar `json:"time_limit" yaml:"time_limit"`
+	Points    scalar `json:"points" yaml:"points"`
+
+	Alternatives list   `json:"alternatives" yaml:"alternatives"`
+	Match        scalar `json:"match" yaml:"match"`
+
+	Type    scalar `json:"type" yaml:"type"`
+	Options list   `json:"options" yaml:"options"`
+
+	Category   scalar `json:"category" yaml:"category"`
+	Tags       list   `json:"tags" yaml:"tags"`
+	Difficulty scalar `json:"difficulty" yaml:"difficulty"`
+
+	Image    scalar `json:"image" yaml:"image"`
+	Audio    scalar `json:"audio" yaml:"audio"`
+	Code     scalar `json:"code" yaml:"code"`
+	Language scalar `json:"language" yaml:"language"`
+}
+
+// scalar is a string that may also be written as a JSON number
+// or boolean, e.g. an answer of 10 rather than "10".
+type scalar string
+
+func (s *scalar) UnmarshalJSON(data []byte) error {
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = scalar(str)
+		return nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw.(type) {
+	case float64, bool:
+		*s = scalar(data)
+		return nil
+	}
+
+	return fmt.Errorf("expected a string, number or boolean but got %s", data)
+}
+
+// list is a list of strings that may also be written as a
+// single scalar. Within a table cell its items are separated by
+// semicolons.
+type list []string
+
+func (l *list) UnmarshalJSON(data []byte) error {
+
+	var items []scalar
+	if err := json.Unmarshal(data, &items); err != nil {
+		var item scalar
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		items = []scalar{item}
+	}
+
+	*l = make(list, len(items))
+	for i, item := range items {
+		(*l)[i] = string(item)
+	}
+
+	return nil
+}
+
+func (l *list) UnmarshalYAML(node *yaml.Node) error {
+
+	if node.Kind == yaml.ScalarNode {
+		*l = list{node.Value}
+		return nil
+	}
+
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+
+	return nil
+}
+
+// splitList splits a table cell into the items of a list.
+func splitList(cell string) list {
+
+	var items list
+	for _, item := range strings.Split(cell, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// problem validates the record and converts it into a Problem.
+func (rec record) problem() (Problem, error) {
+
+	question := strings.TrimSpace(string(rec.Question))
+	if question == "" {
+		return Problem{}, errors.New("missing question")
+	}
+
+	answer := strings.TrimSpace(string(rec.Answer))
+	if answer == "" {
+		return Problem{}, errors.New("missing answer")
+	}
+
+	timeLimit, err := parseTimeLimit(string(rec.TimeLimit))
+	if err != nil {
+		return Problem{}, err
+	}
+
+	var points float64
+	if text := strings.TrimSpace(string(rec.Points)); text != "" {
+		points, err = strconv.ParseFloat(text, 64)
+		if err != nil || points < 0 {
+			return Problem{}, fmt.Errorf("invalid points %q", text)
+		}
+	}
+
+	var alternatives []string
+	for _, alt := range rec.Alternatives {
+		if alt = strings.TrimSpace(alt); alt != "" {
+			alternatives = append(alternatives, alt)
+		}
+	}
+
+	match := strings.TrimSpace(string(rec.Match))
+	if match != "" {
+		matcher, err := ParseMatcher(match)
+		if err != nil {
+			return Problem{}, err
+		}
+		if v, ok := matcher.(answerValidator); ok {
+			for _, a := range append([]string{answer}, alternatives...) {
+				if err := v.ValidateAnswer(a); err != nil {
+					return Problem{}, fmt.Errorf("invalid answer %q: %v", a, err)
+				}
+			}
+		}
+	}
+
+	var tags []string
+	for _, tag := range rec.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	difficulty, err := ParseDifficulty(string(rec.Difficulty))
+	if err != nil {
+		return Problem{}, err
+	}
+
+	image, err := parseMedia("image", string(rec.Image), imageExtensions)
+	if err != nil {
+		return Problem{}, err
+	}
+	audio, err := parseMedia("audio", string(rec.Audio), audioExtensions)
+	if err != nil {
+		return Problem{}, err
+	}
+	code, language, err := parseCode(string(rec.Code), string(rec.Language))
+	if err != nil {
+		return Problem{}, err
+	}
+
+	questionType, err := ParseQuestionType(string(rec.Type))
+	if err != nil {
+		return Problem{}, err
+	}
+	options, err := checkOptions(questionType, rec.Options, answer, alternatives)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	return Problem{
+		Question:     question,
+		Answer:       answer,
+		Alternatives: alternatives,
+		Match:        match,
+		Type:         questionType,
+		Options:      options,
+		TimeLimit:    timeLimit,
+		Points:       points,
+		Category:     strings.TrimSpace(string(rec.Category)),
+		Tags:         tags,
+		Difficulty:   difficulty,
+		Image:        image,
+		Audio:        audio,
+		Code:         code,
+		Language:     language,
+	}, nil
+}
+
+// checkOptions returns the options of a question of the
+// specified type once they are validated against its answers.
+func checkOptions(t QuestionType, opts list, answer string, alternatives []string) ([]string, error) {
+
+	var options []string
+	for _, option := range opts {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+
+	switch t {
+
+	case TypeText:
+		if len(options) > 0 {
+			return nil, errors.New("options given for a text question")
+		}
+
+	case TypeTrueFalse:
+		if len(options) > 0 {
+			return nil, errors.New("options given for a true/false question")
+		}
+		if _, ok := parseBool(normalize(answer)); !ok {
+			return nil, fmt.Errorf("true/false answer %q is neither true nor false", answer)
+		}
+
+	case TypeChoice, TypeMultiSelect:
+		if len(options) < 2 {
+			return nil, fmt.Errorf("expected at least 2 options but got %d", len(options))
+		}
+		if len(options) > 26 {
+			return nil, fmt.Errorf("expected at most 26 options but got %d", len(options))
+		}
+		accepted := []string{answer}
+		if t == TypeMultiSelect {
+			accepted = append(accepted, alternatives...)
+		}
+		for _, a := range accepted {
+			if !containsNormalized(options, a) {
+				return nil, fmt.Errorf("answer %q is not one of the options", a)
+			}
+		}
+
+	}
+
+	return options, nil
+}
+
+// containsNormalized reports whether s is within the list once
+// both are stripped of formatting.
+func containsNormalized(list []string, s string) bool {
+
+	for _, item := range list {
+		if normalize(item) == normalize(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTimeLimit parses a time limit written either in seconds,
+// e.g. "10", or as a duration, e.g. "1m30s". An empty time limit
+// is zero.
+func parseTimeLimit(text string) (time.Duration, error) {
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+
+	limit, err := time.ParseDuration(text)
+	if seconds, parseErr := strconv.ParseFloat(text, 64); parseErr == nil {
+		limit, err = time.Duration(seconds*float64(time.Second)), nil
+	}
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid time limit %q", text)
+	}
+
+	return limit, nil
+}
+
+// columns maps the name of each column that may appear in a
+// tabular problem source to the record field it sets.
+var columns = map[string]func(rec *record, value string) error{
+	"question": func(rec *record, value string) error {
+		rec.Question = scalar(value)
+		return nil
+	},
+	"answer": func(rec *record, value string) error {
+		rec.Answer = scalar(value)
+		return nil
+	},
+	"time_limit": func(rec *record, value string) error {
+		rec.TimeLimit = scalar(value)
+		return nil
+	},
+	"points": func(rec *record, value string) error {
+		rec.Points = scalar(value)
+		return nil
+	},
+	"alternatives": func(rec *record, value string) error {
+		rec.Alternatives = splitList(value)
+		return nil
+	},
+	"match": func(rec *record, value string) error {
+		rec.Match = scalar(value)
+		return nil
+	},
+	"type": func(rec *record, value string) error {
+		rec.Type = scalar(value)
+		return nil
+	},
+	"options": func(rec *record, value string) error {
+		rec.Options = splitList(value)
+		return nil
+	},
+	"category": func(rec *record, value string) error {
+		rec.Category = scalar(value)
+		return nil
+	},
+	"difficulty": func(rec *record, value string) error {
+		rec.Difficulty = scalar(value)
+		return nil
+	},
+	"tags": func(rec *record, value string) error {
+		rec.Tags = splitList(value)
+		return nil
+	},
+	"image": func(rec *record, value string) error {
+		rec.Image = scalar(value)
+		return nil
+	},
+	"audio": func(rec *record, value string) error {
+		rec.Audio = scalar(value)
+		return nil
+	},
+	"code": func(rec *record, value string) error {
+		rec.Code = scalar(value)
+		return nil
+	},
+	"language": func(rec *record, value string) error {
+		rec.Language = scalar(value)
+		return nil
+	},
+}
+
+// positionalColumns is the order of the columns in a tabular
+// problem source without a header row.
+var positionalColumns = []string{
+	"question", "answer", "time_limit", "points", "alternatives", "match",
+	"type", "options", "category", "difficulty", "tags",
+	"image", "audio", "code", "language",
+}
+
+// tableRow is a row of cells from a tabular problem source
+// along with the line it was read from.
+type tableRow struct {
+	line  int
+	cells []string
+}
+
+// parseTable converts rows from a tabular problem source into
+// Problems.
+//
+// If the first row names its columns, e.g. "question,answer",
+// it is used as a header. Otherwise the columns are read in
+// the order of positionalColumns.
+func parseTable(name string, rows []tableRow, header bool) ([]Problem, error) {
+
+	var errs ParseErrors
+
+	names := positionalColumns
+	if len(rows) > 0 && (header || isHeader(rows[0].cells)) {
+		names = make([]string, len(rows[0].cells))
+		for i, cell := range rows[0].cells {
+			names[i] = strings.ToLower(strings.TrimSpace(cell))
+			if _, ok := columns[names[i]]; !ok {
+				errs = append(errs, &ParseError{
+					name, rows[0].line, fmt.Errorf("unknown column %q", cell),
+				})
+			}
+		}
+		rows = rows[1:]
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	problems := make([]Problem, 0, len(rows))
+	for _, row := range rows {
+
+		if len(row.cells) < 2 {
+			errs = append(errs, &ParseError{
+				name, row.line,
+				fmt.Errorf("expected at least 2 columns but got %d", len(row.cells)),
+			})
+			continue
+		}
+		if len(row.cells) > len(names) {
+			errs = append(errs, &ParseError{
+				name, row.line,
+				fmt.Errorf("expected at most %d columns but got %d", len(names), len(row.cells)),
+			})
+			continue
+		}
+
+		p, err := row.problem(names)
+		if err != nil {
+			errs = append(errs, &ParseError{name, row.line, err})
+			continue
+		}
+		p.Line = row.line
+		problems = append(problems, p)
+	}
+
+	return problems, errs.errorOrNil()
+}
+
+// problem fills in a record from the row's cells, which are
+// named by the specified columns, and converts it into a
+// Problem.
+func (row tableRow) problem(names []string) (Problem, error) {
+
+	var rec record
+	for i, cell := range row.cells {
+		if err := columns[names[i]](&rec, strings.TrimSpace(cell)); err != nil {
+			return Problem{}, fmt.Errorf("column %q: %v", names[i], err)
+		}
+	}
+
+	return rec.problem()
+}
+
+// isHeader reports whether the cells name the columns of a
+// tabular problem source, one of which must be "question".
+func isHeader(cells []string) bool {
+
+	question := false
+	for _, cell := range cells {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if _, ok := columns[name]; !ok {
+			return false
+		}
+		question = question || name == "question"
+	}
+
+	return question
+}
@@ -0,0 +1,204 @@
+package quiz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Cache keeps a copy on disk of each problem source fetched over
+// HTTP, along with its ETag, so that a source is only downloaded
+// again once it has changed.
+type Cache struct {
+	Dir    string
+	Client *http.Client
+}
+
+// NewCache returns a Cache that keeps its copies in the
+// specified directory.
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir, Client: http.DefaultClient}
+}
+
+// DefaultCacheDir returns the directory sources are cached in
+// by default, within the user's cache directory.
+func DefaultCacheDir() string {
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "quiz")
+}
+
+// isURL reports whether the source is an http(s) URL rather than
+// a local path.
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Fetch returns the path of the cached copy of the source at
+// the specified URL, first downloading it unless the server
+// reports that the copy is still current.
+//
+// If the server cannot be reached, an earlier copy is used so
+// that quizzes can still be taken offline.
+func (c *Cache) Fetch(rawURL string) (string, error) {
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u.Fragment = ""
+
+	// The copy keeps the extension of the URL, so that its format
+	// can still be told from it.
+	sum := sha256.Sum256([]byte(u.String()))
+	cached := filepath.Join(c.Dir, hex.EncodeToString(sum[:16])+path.Ext(u.Path))
+	etagPath := cached + ".etag"
+
+	_, statErr := os.Stat(cached)
+	haveCopy := statErr == nil
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if haveCopy {
+		if etag, err := os.ReadFile(etagPath); err == nil {
+			req.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if haveCopy {
+			return cached, nil
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCopy:
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%s: %s", u, resp.Status)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return "", err
+	}
+	if err := writeAtomic(cached, resp.Body); err != nil {
+		return "", err
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		if err := os.Remove(etagPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		return cached, nil
+	}
+	if err := os.WriteFile(etagPath, []byte(etag), 0644); err != nil {
+		return "", err
+	}
+
+	return cached, nil
+}
+
+// writeAtomic writes the contents of r to the file at the
+// specified path, replacing it only once it is written in full.
+func writeAtomic(path string, r io.Reader) error {
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// LoadURL parses Problems from the source at the specified URL,
+// using the copy kept in the cache if it is still current. If
+// format is empty it is chosen by the extension of the URL's
+// path, and a URL of a .zip file is loaded as a Bundle.
+func LoadURL(rawURL string, format string, cache *Cache) ([]Problem, error) {
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" && !strings.EqualFold(path.Ext(u.Path), ".zip") {
+		if format, err = FormatFromPath(u.Path); err != nil {
+			return nil, fmt.Errorf("%s: %v (set the format explicitly)", rawURL, err)
+		}
+	}
+
+	cached, err := cache.Fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		return loadBundle(rawURL, cached, u.Fragment)
+	}
+
+	f, err := os.Open(cached)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Media are fetched from alongside the source.
+	problems, err := Load(rawURL, f, format)
+	resolveMedia(problems, func(ref string) string {
+		return u.ResolveReference(&url.URL{Path: ref}).String()
+	})
+
+	return problems, err
+}
+
+// LoadSource parses Problems from a source which may be a local
+// file, an http(s) URL or a Bundle. A single bank of a Bundle is
+// chosen by naming it after a "#", e.g. "banks.zip#geography".
+// Fetched sources are kept in the cache. If format is empty it
+// is chosen by file extension.
+func LoadSource(source string, format string, cache *Cache) ([]Problem, error) {
+
+	if isURL(source) {
+		return LoadURL(source, format, cache)
+	}
+
+	bundlePath, bank := source, ""
+	if i := strings.LastIndex(source, "#"); i >= 0 {
+		bundlePath, bank = source[:i], source[i+1:]
+	}
+	if isBundle(bundlePath) {
+		return loadBundle(source, bundlePath, bank)
+	}
+
+	return LoadFile(source, format)
+}
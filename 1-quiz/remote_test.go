@@ -0,0 +1,159 @@
+package quiz
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+// sourceServer serves a problem source with an ETag, replying
+// 304 Not Modified to requests that already have it.
+type sourceServer struct {
+	mu          sync.Mutex
+	body        string
+	etag        string
+	notModified int
+}
+
+func (s *sourceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	fmt.Fprint(w, s.body)
+}
+
+// notModifiedCount returns how many requests were replied to
+// with 304 Not Modified.
+func (s *sourceServer) notModifiedCount() int {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.notModified
+}
+
+// update changes the source and its ETag.
+func (s *sourceServer) update(body string, etag string) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.body, s.etag = body, etag
+}
+
+func readFile(t *testing.T, path string) string {
+
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestFetchStoresETag(t *testing.T) {
+
+	source := &sourceServer{body: "5+5,10\n", etag: `"v1"`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+	cache := NewCache(t.TempDir())
+
+	cached, err := cache.Fetch(server.URL + "/problems.csv")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if got := readFile(t, cached); got != "5+5,10\n" {
+		t.Errorf("cached %q, want %q", got, "5+5,10\n")
+	}
+	if got := readFile(t, cached+".etag"); got != `"v1"` {
+		t.Errorf("stored the ETag %q, want %q", got, `"v1"`)
+	}
+}
+
+func TestFetchNotModified(t *testing.T) {
+
+	source := &sourceServer{body: "5+5,10\n", etag: `"v1"`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+	cache := NewCache(t.TempDir())
+	url := server.URL + "/problems.csv"
+
+	first, err := cache.Fetch(url)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	second, err := cache.Fetch(url)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if n := source.notModifiedCount(); n != 1 {
+		t.Errorf("the server replied Not Modified %d times, want 1", n)
+	}
+	if second != first || readFile(t, second) != "5+5,10\n" {
+		t.Errorf("got %s (%q), want the cached copy %s", second, readFile(t, second), first)
+	}
+
+	// A changed source is downloaded again.
+	source.update("1+1,2\n", `"v2"`)
+	third, err := cache.Fetch(url)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got := readFile(t, third); got != "1+1,2\n" {
+		t.Errorf("cached %q once the source changed, want %q", got, "1+1,2\n")
+	}
+	if got := readFile(t, third+".etag"); got != `"v2"` {
+		t.Errorf("stored the ETag %q once the source changed, want %q", got, `"v2"`)
+	}
+}
+
+func TestFetchOffline(t *testing.T) {
+
+	source := &sourceServer{body: "5+5,10\n1+1,2\n", etag: `"v1"`}
+	server := httptest.NewServer(source)
+	cache := NewCache(t.TempDir())
+	url := server.URL + "/problems.csv"
+
+	if _, err := LoadURL(url, "", cache); err != nil {
+		t.Fatalf("LoadURL: %v", err)
+	}
+	server.Close()
+
+	problems, err := LoadURL(url, "", cache)
+	if err != nil {
+		t.Fatalf("LoadURL with the server down: %v", err)
+	}
+	if len(problems) != 2 || problems[0].Question != "5+5" {
+		t.Errorf("got %+v from the cache, want its 2 problems", problems)
+	}
+
+	// There is nothing to fall back to without a copy.
+	if _, err := NewCache(t.TempDir()).Fetch(url); err == nil {
+		t.Error("Fetch with the server down and no copy: got no error")
+	}
+}
+
+func TestFetchError(t *testing.T) {
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := NewCache(t.TempDir()).Fetch(server.URL + "/missing.csv"); err == nil {
+		t.Error("Fetch of a missing source: got no error")
+	}
+}
@@ -0,0 +1,302 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterReport("json", WriteJSONReport, ".json")
+	RegisterReport("csv", WriteCSVReport, ".csv")
+	RegisterReport("junit", WriteJUnitReport, ".xml")
+}
+
+// Reporter writes the details of an Attempt in a machine-readable
+// report format.
+type Reporter func(w io.Writer, attempt Attempt) error
+
+var reporters = map[string]Reporter{}
+var reportExtensions = map[string]string{}
+
+// RegisterReport makes a Reporter available under the specified
+// format name and file extensions, e.g. "junit" and ".xml".
+func RegisterReport(format string, reporter Reporter, exts ...string) {
+
+	reporters[format] = reporter
+	for _, ext := range exts {
+		reportExtensions[strings.ToLower(ext)] = format
+	}
+}
+
+// Reports returns the names of all registered report formats.
+func Reports() []string {
+
+	var formats []string
+	for format := range reporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// WriteReport writes a report of the Attempt to the file at the
+// specified path. The path may be prefixed with the report
+// format, e.g. "junit:results.txt"; otherwise the format is
+// chosen by file extension.
+func WriteReport(path string, attempt Attempt) error {
+
+	format := ""
+	if i := strings.Index(path, ":"); i > 0 {
+		if _, ok := reporters[path[:i]]; ok {
+			format, path = path[:i], path[i+1:]
+		}
+	}
+	if format == "" {
+		ext := strings.ToLower(filepath.Ext(path))
+		var ok bool
+		if format, ok = reportExtensions[ext]; !ok {
+			return fmt.Errorf(
+				"%s: unknown report file extension %q (prefix the path with one of %s, e.g. json:%s)",
+				path, ext, strings.Join(Reports(), ", "), path,
+			)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporters[format](f, attempt); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// jsonReport is an Attempt as it is written by WriteJSONReport,
+// whose answers give their latency in seconds.
+type jsonReport struct {
+	Attempt
+	Answers []jsonAnswer `json:"answers"`
+}
+
+type jsonAnswer struct {
+	Question       string  `json:"question"`
+	Category       string  `json:"category,omitempty"`
+	Expected       string  `json:"expected"`
+	Response       string  `json:"response"`
+	Correct        bool    `json:"correct"`
+	Points         float64 `json:"points"`
+	TimedOut       bool    `json:"timed_out,omitempty"`
+	LatencySeconds float64 `json:"latency_seconds"`
+	Flags          []Flag  `json:"flags,omitempty"`
+	Hash           string  `json:"hash,omitempty"`
+}
+
+// WriteJSONReport writes the Attempt as indented JSON, in the
+// form it is recorded in the History apart from the latency of
+// each answer, which is given in seconds as latency_seconds like
+// in the other reports, to the millisecond.
+func WriteJSONReport(w io.Writer, attempt Attempt) error {
+
+	report := jsonReport{Attempt: attempt, Answers: make([]jsonAnswer, len(attempt.Answers))}
+	for i, a := range attempt.Answers {
+		report.Answers[i] = jsonAnswer{
+			Question:       a.Question,
+			Category:       a.Category,
+			Expected:       a.Expected,
+			Response:       a.Response,
+			Correct:        a.Correct,
+			Points:         a.Points,
+			TimedOut:       a.TimedOut,
+			LatencySeconds: math.Round(a.Latency.Seconds()*1000) / 1000,
+			Flags:          a.Flags,
+			Hash:           a.Hash,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
+
+// WriteCSVReport writes a row of CSV for each answer in the
+// Attempt, after a header row. Latency is in seconds, and any
+// Flags are separated by semicolons.
+func WriteCSVReport(w io.Writer, attempt Attempt) error {
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write([]string{
+		"number", "question", "category", "response", "expected",
+		"correct", "timed_out", "points", "latency", "flags",
+	})
+	for i, a := range attempt.Answers {
+		csvWriter.Write([]string{
+			strconv.Itoa(i + 1),
+			a.Question,
+			a.Category,
+			a.Response,
+			a.Expected,
+			strconv.FormatBool(a.Correct),
+			strconv.FormatBool(a.TimedOut),
+			strconv.FormatFloat(a.Points, 'g', -1, 64),
+			strconv.FormatFloat(a.Latency.Seconds(), 'f', 3, 64),
+			joinFlags(a.Flags),
+		})
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// joinFlags joins Flags into a single CSV cell.
+func joinFlags(flags []Flag) string {
+
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = string(flag)
+	}
+
+	return strings.Join(names, ";")
+}
+
+// junitSuites is the root of a JUnit XML report.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitFailure `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// WriteJUnitReport writes the Attempt as a JUnit XML test suite
+// with a test case for each question. Incorrect answers are
+// failures, and questions left unasked are skipped.
+func WriteJUnitReport(w io.Writer, attempt Attempt) error {
+
+	suite := junitSuite{
+		Name: attempt.Source,
+		Properties: []junitProperty{
+			{"user", attempt.User},
+			{"seed", strconv.FormatInt(attempt.Seed, 10)},
+			{"score", strconv.FormatFloat(attempt.Score, 'g', -1, 64)},
+			{"max_score", strconv.FormatFloat(attempt.MaxScore, 'g', -1, 64)},
+		},
+	}
+	if !attempt.Started.IsZero() {
+		suite.Timestamp = attempt.Started.Format("2006-01-02T15:04:05")
+	}
+
+	var total float64
+	for i, a := range attempt.Answers {
+
+		className := a.Category
+		if className == "" {
+			className = attempt.Source
+		}
+		c := junitCase{
+			Name:      fmt.Sprintf("%d. %s", i+1, a.Question),
+			ClassName: className,
+			Time:      seconds(a.Latency.Seconds()),
+		}
+		total += a.Latency.Seconds()
+
+		switch {
+		case a.TimedOut:
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("timed out; expected %q", a.Expected),
+				Type:    "timeout",
+			}
+		case !a.Correct:
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("expected %q but got %q", a.Expected, a.Response),
+				Type:    "incorrect",
+			}
+		}
+		if c.Failure != nil {
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	for i := len(attempt.Answers); i < attempt.Total; i++ {
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:      fmt.Sprintf("%d. (not asked)", i+1),
+			ClassName: attempt.Source,
+			Time:      seconds(0),
+			Skipped:   &junitFailure{Message: "the quiz ended before the question was asked"},
+		})
+		suite.Skipped++
+	}
+
+	suite.Tests = len(suite.Cases)
+	suite.Time = seconds(total)
+
+	report := junitSuites{
+		Name:     "quiz",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// seconds formats a number of seconds for a JUnit report.
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
@@ -0,0 +1,131 @@
+package quiz
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the reports in testdata")
+
+// newReportAttempt returns an Attempt with a correct, an incorrect
+// and a timed out answer, and two questions it did not reach.
+func newReportAttempt() Attempt {
+
+	return Attempt{
+		User:     "ada",
+		Source:   "problems.csv",
+		Started:  time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Seed:     42,
+		Score:    2,
+		MaxScore: 6,
+		Correct:  1,
+		Total:    5,
+		Answers: []AnswerRecord{
+			{
+				Question: "5+5",
+				Category: "arithmetic",
+				Expected: "10",
+				Response: "10",
+				Correct:  true,
+				Points:   2,
+				Latency:  1500 * time.Millisecond,
+			},
+			{
+				Question: `What is "a, b"?`,
+				Expected: "a pair",
+				Response: "<none>",
+				Points:   1,
+				Latency:  250 * time.Millisecond,
+				Flags:    []Flag{FlagFast, FlagPasted},
+			},
+			{
+				Question: "7*6",
+				Category: "arithmetic",
+				Expected: "42",
+				Points:   1,
+				TimedOut: true,
+				Latency:  30 * time.Second,
+			},
+		},
+	}
+}
+
+func TestReporters(t *testing.T) {
+
+	tests := []struct {
+		reporter Reporter
+		golden   string
+	}{
+		{WriteJSONReport, "report.json"},
+		{WriteCSVReport, "report.csv"},
+		{WriteJUnitReport, "report.xml"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.reporter(&buf, newReportAttempt()); err != nil {
+			t.Fatalf("%s: %v", test.golden, err)
+		}
+
+		path := filepath.Join("testdata", test.golden)
+		if *update {
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != string(want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.golden, buf.String(), want)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		path   string
+		prefix string
+		err    string
+	}{
+		{"a.json", "{", ""},
+		{"a.CSV", "number,", ""},
+		{"a.xml", "<?xml", ""},
+		{"junit:a.txt", "<?xml", ""},
+		{"csv:a.json", "number,", ""},
+		{"a.txt", "", `unknown report file extension ".txt"`},
+		{"html:a.txt", "", `unknown report file extension ".txt"`},
+	}
+
+	for _, test := range tests {
+		format, name := "", test.path
+		if i := strings.Index(name, ":"); i >= 0 {
+			format, name = name[:i+1], name[i+1:]
+		}
+		err := WriteReport(format+filepath.Join(dir, name), newReportAttempt())
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want one containing %q", test.path, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+			continue
+		}
+
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if !strings.HasPrefix(string(data), test.prefix) {
+			t.Errorf("%s: got %.20q, want a report starting with %q", test.path, data, test.prefix)
+		}
+	}
+}
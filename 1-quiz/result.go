@@ -0,0 +1,111 @@
+package quiz
+
+import "time"
+
+// Answer is the user's response to a single Problem.
+type Answer struct {
+	Problem  Problem
+	Response string
+	Correct  bool
+
+	// Credit is the fraction of the Problem's points earned by
+	// the response, from 0 to 1. Only full credit is Correct.
+	Credit float64
+
+	// TimedOut is set if the Problem's TimeLimit was exceeded
+	// before a response was given.
+	TimedOut bool
+
+	// Latency is the time taken to respond once the question
+	// was asked.
+	Latency time.Duration
+
+	// Points is the score given for the response.
+	Points float64
+
+	// Flags mark a response whose integrity is in doubt, e.g.
+	// one given implausibly fast.
+	Flags []Flag
+
+	// Hash links the answer into the keyed hash chain of the
+	// answers before it, if the Quiz has a Key.
+	Hash string
+}
+
+// Result is the outcome of a Quiz run.
+//
+// Answers holds the answer to every Problem that was asked, in
+// order. Problems left unasked when the quiz ended early still
+// count towards the Total and MaxScore, up to the Quiz's number
+// of Questions.
+type Result struct {
+	Answers  []Answer
+	Correct  int
+	Total    int
+	Score    float64
+	MaxScore float64
+
+	// Seed is the seed the options of each question were
+	// shuffled with.
+	Seed int64
+
+	// Started is when the user began the quiz.
+	Started time.Time
+}
+
+// newSessionResult returns an empty Result for a Quiz of which
+// only the specified number of Problems are asked. Until they
+// have been, its MaxScore is estimated from the average value
+// of the Problems.
+func newSessionResult(problems []Problem, questions int) Result {
+
+	result := NewResult(problems)
+	if total := questionCount(len(problems), questions); total < len(problems) {
+		result.MaxScore *= float64(total) / float64(len(problems))
+		result.Total = total
+	}
+
+	return result
+}
+
+// questionCount returns how many of the problems are asked when
+// the specified number of questions is, where zero asks them
+// all.
+func questionCount(problems int, questions int) int {
+
+	if questions > 0 && questions < problems {
+		return questions
+	}
+
+	return problems
+}
+
+// NewResult returns an empty Result for a Quiz of the
+// specified Problems.
+func NewResult(problems []Problem) Result {
+
+	result := Result{Total: len(problems)}
+	for _, p := range problems {
+		result.MaxScore += p.Value()
+	}
+
+	return result
+}
+
+// Add records an answer and scores it by its Credit. A Correct
+// answer is given full credit.
+func (r *Result) Add(a Answer) {
+
+	if a.Correct {
+		a.Credit = 1
+	}
+	a.Correct = a.Credit >= 1
+	if a.Correct {
+		r.Correct++
+	}
+
+	a.Points = a.Credit * a.Problem.Value()
+	r.Score += a.Points
+
+	r.Answers = append(r.Answers, a)
+}
@@ -0,0 +1,135 @@
+package quiz
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Review is the spaced-repetition schedule of a question for a
+// user, as maintained by the SM-2 algorithm.
+type Review struct {
+	// EaseFactor scales the interval after each successful
+	// review. It starts at 2.5 and never falls below 1.3.
+	EaseFactor float64 `json:"ease_factor"`
+
+	// Interval is the number of days until the next review.
+	Interval int `json:"interval"`
+
+	// Repetitions is the number of successful reviews in a row.
+	Repetitions int `json:"repetitions"`
+
+	// Due is when the question should next be asked.
+	Due time.Time `json:"due"`
+}
+
+// NewReview returns the schedule of a question that has never
+// been reviewed, which is due immediately.
+func NewReview() Review {
+	return Review{EaseFactor: 2.5}
+}
+
+// Schedule returns the Review rescheduled following a response
+// of the specified quality at the specified time.
+//
+// Quality grades the response from 0, a complete blackout, to 5,
+// a perfect response. Responses of quality 3 or more count as
+// successful reviews.
+func (r Review) Schedule(quality int, now time.Time) Review {
+
+	if quality >= 3 {
+		switch r.Repetitions {
+		case 0:
+			r.Interval = 1
+		case 1:
+			r.Interval = 6
+		default:
+			r.Interval = int(math.Round(float64(r.Interval) * r.EaseFactor))
+		}
+		r.Repetitions++
+	} else {
+		r.Repetitions = 0
+		r.Interval = 1
+	}
+
+	q := float64(5 - quality)
+	r.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if r.EaseFactor < 1.3 {
+		r.EaseFactor = 1.3
+	}
+
+	r.Due = now.AddDate(0, 0, r.Interval)
+
+	return r
+}
+
+// Quality grades an Answer for the SM-2 algorithm from its
+// correctness and how quickly it was given.
+func Quality(a Answer) int {
+
+	switch {
+	case a.TimedOut:
+		return 0
+	case a.Credit == 0:
+		return 1
+	case !a.Correct:
+		return 2
+	case a.Latency < 5*time.Second:
+		return 5
+	case a.Latency < 15*time.Second:
+		return 4
+	}
+
+	return 3
+}
+
+// DueProblems returns the problems whose Review is due at the
+// specified time, the most overdue first, followed by the
+// problems that have never been reviewed. Reviews are keyed by
+// question.
+func DueProblems(problems []Problem, reviews map[string]Review, now time.Time) []Problem {
+
+	var due, unseen []Problem
+	for _, p := range problems {
+		review, ok := reviews[p.Question]
+		switch {
+		case !ok:
+			unseen = append(unseen, p)
+		case !review.Due.After(now):
+			due = append(due, p)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return reviews[due[i].Question].Due.Before(reviews[due[j].Question].Due)
+	})
+
+	return append(due, unseen...)
+}
+
+// NextDue returns when the earliest of the Reviews is next due,
+// or the zero time if there are none.
+func NextDue(reviews map[string]Review) time.Time {
+
+	var next time.Time
+	for _, review := range reviews {
+		if next.IsZero() || review.Due.Before(next) {
+			next = review.Due
+		}
+	}
+
+	return next
+}
+
+// UpdateReviews reschedules the Review of the question of each
+// Answer at the specified time.
+func UpdateReviews(reviews map[string]Review, answers []Answer, now time.Time) {
+
+	for _, a := range answers {
+		review, ok := reviews[a.Problem.Question]
+		if !ok {
+			review = NewReview()
+		}
+		reviews[a.Problem.Question] = review.Schedule(Quality(a), now)
+	}
+}
@@ -0,0 +1,428 @@
+package quiz
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DEFAULT_QUESTION_TIME is the time given to answer a question
+// in a Room when the Problem has no TimeLimit of its own.
+const DEFAULT_QUESTION_TIME = 20 * time.Second
+
+// ROOM_POINTS is the most a Room awards for a correct answer to
+// a question worth a single point.
+const ROOM_POINTS = 1000
+
+var (
+	ErrNameTaken       = errors.New("name is already taken")
+	ErrRoomStarted     = errors.New("room has already started")
+	ErrNotAsking       = errors.New("no question is open")
+	ErrAlreadyAnswered = errors.New("question was already answered")
+	ErrUnknownPlayer   = errors.New("unknown player")
+)
+
+// RoomEvent is pushed to every Player in a Room as the host
+// drives the quiz. Type is one of "joined", "left", "question",
+// "answered", "reveal" or "finished".
+type RoomEvent struct {
+	Type string `json:"type"`
+
+	// Player is the name of the player who joined, left or
+	// answered.
+	Player string `json:"player,omitempty"`
+
+	// Question is set on "question" events.
+	Question *QuestionView `json:"question,omitempty"`
+
+	// Answer is the expected answer, set on "reveal" events.
+	Answer string `json:"answer,omitempty"`
+
+	// Leaderboard is set on every event but "question".
+	Leaderboard []Standing `json:"leaderboard,omitempty"`
+}
+
+// Standing is a Player's position on a Room's leaderboard.
+type Standing struct {
+	Name    string  `json:"name"`
+	Score   float64 `json:"score"`
+	Correct int     `json:"correct"`
+
+	// Answered reports whether the player has answered the open
+	// question.
+	Answered bool `json:"answered"`
+}
+
+// Player is a participant in a Room.
+type Player struct {
+	Name    string
+	Score   float64
+	Correct int
+
+	events   chan RoomEvent
+	answered bool
+}
+
+// Events returns the channel on which the Room pushes events to
+// the Player. It is closed when the Player leaves. Events are
+// dropped for a Player that falls too far behind.
+func (p *Player) Events() <-chan RoomEvent {
+	return p.events
+}
+
+// Room is a live quiz driven by a host, in which every question
+// is put to all of the players at once. Answers are scored on
+// both correctness and speed.
+type Room struct {
+	Code string
+
+	// QuestionTime is the time given to answer a question when
+	// the Problem has no TimeLimit of its own.
+	QuestionTime time.Duration
+
+	quiz  *Quiz
+	clock Clock
+	rng   *rand.Rand
+
+	mu        sync.Mutex
+	players   map[string]*Player
+	order     []string
+	observers []chan RoomEvent
+	number    int
+	prompt    *Prompt
+	asked     time.Time
+	open      bool
+	finished  bool
+}
+
+// NewRoom returns a Room with the specified join code that
+// gives the Quiz, timed by the clock.
+func NewRoom(code string, q *Quiz, clock Clock) *Room {
+
+	seed := q.Seed
+	if seed == 0 {
+		seed = clock.Now().UnixNano()
+	}
+
+	return &Room{
+		Code:         code,
+		QuestionTime: DEFAULT_QUESTION_TIME,
+		quiz:         q,
+		clock:        clock,
+		rng:          rand.New(rand.NewSource(seed)),
+		players:      map[string]*Player{},
+	}
+}
+
+// NewRoomCode returns a random four-letter join code.
+func NewRoomCode() string {
+
+	const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	code := make([]byte, 4)
+	for i := range code {
+		code[i] = letters[rand.Intn(len(letters))]
+	}
+
+	return string(code)
+}
+
+// Join adds a player with the specified name to the Room.
+// Players may only join before the first question is asked.
+func (r *Room) Join(name string) (*Player, error) {
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.number > 0 {
+		return nil, ErrRoomStarted
+	}
+	if _, ok := r.players[name]; ok {
+		return nil, ErrNameTaken
+	}
+
+	p := &Player{Name: name, events: make(chan RoomEvent, 16)}
+	r.players[name] = p
+	r.order = append(r.order, name)
+	r.broadcast(RoomEvent{Type: "joined", Player: name, Leaderboard: r.leaderboard()})
+
+	return p, nil
+}
+
+// Leave removes a player from the Room and closes their events.
+func (r *Room) Leave(name string) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.players[name]
+	if !ok {
+		return
+	}
+	delete(r.players, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	close(p.events)
+
+	r.broadcast(RoomEvent{Type: "left", Player: name, Leaderboard: r.leaderboard()})
+	r.closeIfAllAnswered()
+}
+
+// Watch returns a channel on which every event in the Room is
+// pushed, e.g. to show the leaderboard to the host. Events are
+// dropped if they are not received. The channel is closed once
+// the Room has finished, after the "finished" event.
+func (r *Room) Watch() <-chan RoomEvent {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make(chan RoomEvent, 16)
+	if r.finished {
+		close(events)
+		return events
+	}
+	r.observers = append(r.observers, events)
+
+	return events
+}
+
+// Next puts the next question to every player. The question is
+// closed once everyone has answered or its time runs out. Next
+// returns false once every question has been asked, at which
+// point the Room is finished.
+func (r *Room) Next() bool {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.open {
+		r.closeQuestion()
+	}
+	if r.finished {
+		return false
+	}
+	if r.number >= r.quiz.Total() {
+		r.finish()
+		return false
+	}
+
+	prompt := r.quiz.Problems[r.number].Prompt(r.rng)
+	r.prompt = &prompt
+	r.number++
+	r.asked = r.clock.Now()
+	r.open = true
+	for _, p := range r.players {
+		p.answered = false
+	}
+
+	limit := r.limit()
+	question := NewQuestionView(r.number, r.quiz.Total(), prompt)
+	question.TimeLimit = limit.Seconds()
+	r.broadcast(RoomEvent{Type: "question", Question: &question})
+
+	number := r.number
+	timeout := r.clock.After(limit)
+	go func() {
+		<-timeout
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.open && r.number == number {
+			r.closeQuestion()
+		}
+	}()
+
+	return true
+}
+
+// Answer grades a player's response to the open question. The
+// points awarded for a correct answer fall from ROOM_POINTS to
+// half of that as the question's time runs out.
+func (r *Room) Answer(name string, response string) (Answer, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.players[name]
+	if !ok {
+		return Answer{}, ErrUnknownPlayer
+	}
+	if !r.open {
+		return Answer{}, ErrNotAsking
+	}
+	if p.answered {
+		return Answer{}, ErrAlreadyAnswered
+	}
+
+	limit := r.limit()
+	latency := r.clock.Now().Sub(r.asked)
+	if latency >= limit {
+		r.closeQuestion()
+		return Answer{}, ErrNotAsking
+	}
+
+	answer := Answer{
+		Problem:  r.prompt.Problem,
+		Response: response,
+		Credit:   r.prompt.Grade(response),
+		Latency:  latency,
+	}
+	answer.Correct = answer.Credit >= 1
+
+	speed := 1 - 0.5*latency.Seconds()/limit.Seconds()
+	answer.Points = math.Round(ROOM_POINTS * answer.Credit * r.prompt.Problem.Value() * speed)
+
+	p.answered = true
+	p.Score += answer.Points
+	if answer.Correct {
+		p.Correct++
+	}
+
+	r.broadcast(RoomEvent{Type: "answered", Player: name, Leaderboard: r.leaderboard()})
+	r.closeIfAllAnswered()
+
+	return answer, nil
+}
+
+// End finishes the Room early, leaving any remaining questions
+// unasked.
+func (r *Room) End() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.open {
+		r.closeQuestion()
+	}
+	if !r.finished {
+		r.finish()
+	}
+}
+
+// Finished reports whether the Room has finished.
+func (r *Room) Finished() bool {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.finished
+}
+
+// Open reports whether a question is open for answers.
+func (r *Room) Open() bool {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.open
+}
+
+// Leaderboard returns every player's Standing, the highest
+// score first.
+func (r *Room) Leaderboard() []Standing {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.leaderboard()
+}
+
+func (r *Room) leaderboard() []Standing {
+
+	standings := make([]Standing, 0, len(r.order))
+	for _, name := range r.order {
+		p := r.players[name]
+		standings = append(standings, Standing{
+			Name:     p.Name,
+			Score:    p.Score,
+			Correct:  p.Correct,
+			Answered: r.open && p.answered,
+		})
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Score > standings[j].Score
+	})
+
+	return standings
+}
+
+// limit returns the time given to answer the open question.
+func (r *Room) limit() time.Duration {
+
+	if r.prompt.Problem.TimeLimit > 0 {
+		return r.prompt.Problem.TimeLimit
+	}
+
+	return r.QuestionTime
+}
+
+// closeIfAllAnswered closes the open question once every player
+// has answered it.
+func (r *Room) closeIfAllAnswered() {
+
+	if !r.open || len(r.players) == 0 {
+		return
+	}
+	for _, p := range r.players {
+		if !p.answered {
+			return
+		}
+	}
+
+	r.closeQuestion()
+}
+
+// closeQuestion stops accepting answers to the open question and
+// reveals its answer.
+func (r *Room) closeQuestion() {
+
+	r.open = false
+	r.broadcast(RoomEvent{
+		Type:        "reveal",
+		Answer:      r.prompt.Problem.Answer,
+		Leaderboard: r.leaderboard(),
+	})
+}
+
+// finish ends the Room with the final leaderboard, and closes
+// the events of its observers.
+func (r *Room) finish() {
+
+	r.finished = true
+	r.broadcast(RoomEvent{Type: "finished", Leaderboard: r.leaderboard()})
+	for _, events := range r.observers {
+		close(events)
+	}
+	r.observers = nil
+}
+
+// broadcast pushes an event to every player and observer,
+// dropping it for any whose events are not being received.
+func (r *Room) broadcast(event RoomEvent) {
+
+	for _, p := range r.players {
+		select {
+		case p.events <- event:
+		default:
+		}
+	}
+	for _, events := range r.observers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package quiz
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// RoomServer is an http.Handler that lets players join Rooms by
+// their code over WebSockets.
+//
+// A player connects to /ws?code={code}&name={name} and is then
+// sent every RoomEvent as JSON. Answers are sent to the server
+// as {"type": "answer", "response": "..."} and the player is sent
+// {"type": "result", ...} in reply. Errors are sent as
+// {"type": "error", "error": "..."}.
+type RoomServer struct {
+	mu    sync.Mutex
+	rooms map[string]*Room
+
+	upgrader websocket.Upgrader
+}
+
+// NewRoomServer returns a RoomServer without any Rooms.
+func NewRoomServer() *RoomServer {
+	return &RoomServer{rooms: map[string]*Room{}}
+}
+
+// Add makes a Room available to join by its code.
+func (s *RoomServer) Add(room *Room) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rooms[strings.ToUpper(room.Code)] = room
+}
+
+// Remove stops a Room from being joined.
+func (s *RoomServer) Remove(code string) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.rooms, strings.ToUpper(code))
+}
+
+// ServeHTTP serves the player page, or connects a player to a
+// Room over a WebSocket.
+func (s *RoomServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch r.URL.Path {
+	case "/":
+		roomTemplate.Execute(w, nil)
+	case "/ws":
+		s.serveWebSocket(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// roomMessage is a message sent between a player and the server
+// that is not a RoomEvent.
+type roomMessage struct {
+	Type     string  `json:"type"`
+	Response string  `json:"response,omitempty"`
+	Correct  bool    `json:"correct,omitempty"`
+	Points   float64 `json:"points,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// serveWebSocket joins a player to the Room named by the request
+// and relays messages until they disconnect.
+func (s *RoomServer) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+
+	code := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("code")))
+	name := r.URL.Query().Get("name")
+
+	s.mu.Lock()
+	room, ok := s.rooms[code]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown room", http.StatusNotFound)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Writes are serialized as the events and the replies to
+	// answers are sent from separate goroutines.
+	var writeMu sync.Mutex
+	write := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(v)
+	}
+
+	player, err := room.Join(name)
+	if err != nil {
+		write(roomMessage{Type: "error", Error: err.Error()})
+		return
+	}
+	defer room.Leave(player.Name)
+
+	go func() {
+		for event := range player.Events() {
+			if err := write(event); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		var msg roomMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		if msg.Type != "answer" {
+			write(roomMessage{Type: "error", Error: "unknown message type " + msg.Type})
+			continue
+		}
+
+		answer, err := room.Answer(player.Name, msg.Response)
+		if err != nil {
+			write(roomMessage{Type: "error", Error: err.Error()})
+			continue
+		}
+		write(roomMessage{Type: "result", Correct: answer.Correct, Points: answer.Points})
+	}
+}
+
+const ROOM_TEMPLATE = `
+<!DOCTYPE html>
+<html>
+<head><title>Quiz Room</title></head>
+<body>
+	<div id="join">
+		<h1>Join a quiz</h1>
+		<input id="code" placeholder="Room code">
+		<input id="name" placeholder="Your name">
+		<button onclick="join()">Join</button>
+	</div>
+	<div id="room" hidden>
+		<h1 id="question">Waiting for the host to begin...</h1>
+		<div id="options"></div>
+		<form id="text" onsubmit="answer(this.response.value); return false;" hidden>
+			<input name="response" autocomplete="off">
+			<button type="submit">Answer</button>
+		</form>
+		<p id="status"></p>
+		<h2>Leaderboard</h2>
+		<ol id="leaderboard"></ol>
+	</div>
+	<script>
+		var socket;
+		function $(id) { return document.getElementById(id); }
+		function join() {
+			var params = "code=" + encodeURIComponent($("code").value) +
+				"&name=" + encodeURIComponent($("name").value);
+			var scheme = location.protocol === "https:" ? "wss://" : "ws://";
+			socket = new WebSocket(scheme + location.host + "/ws?" + params);
+			socket.onopen = function() { $("join").hidden = true; $("room").hidden = false; };
+			socket.onmessage = function(e) { handle(JSON.parse(e.data)); };
+			socket.onclose = function() { $("status").textContent = "Disconnected."; };
+		}
+		function answer(response) {
+			socket.send(JSON.stringify({type: "answer", response: response}));
+			$("options").innerHTML = "";
+			$("text").hidden = true;
+		}
+		function handle(msg) {
+			if (msg.leaderboard) {
+				$("leaderboard").innerHTML = "";
+				msg.leaderboard.forEach(function(s) {
+					var li = document.createElement("li");
+					li.textContent = s.name + ": " + s.score + (s.answered ? " ✓" : "");
+					$("leaderboard").appendChild(li);
+				});
+			}
+			switch (msg.type) {
+			case "question":
+				var q = msg.question;
+				$("question").textContent = q.number + ". " + q.question + "?";
+				$("status").textContent = "You have " + q.time_limit + " seconds.";
+				$("options").innerHTML = "";
+				$("text").hidden = (q.options || []).length > 0 && q.type !== "multi";
+				$("text").response.value = "";
+				(q.options || []).forEach(function(o) {
+					// Multi-select options are chosen by typing letters.
+					var b = document.createElement(q.type === "multi" ? "div" : "button");
+					b.textContent = o.letter + ") " + o.text;
+					b.onclick = function() { if (q.type !== "multi") answer(o.letter); };
+					$("options").appendChild(b);
+				});
+				break;
+			case "result":
+				$("status").textContent = msg.correct ? "Correct! +" + (msg.points || 0) : "Incorrect.";
+				break;
+			case "reveal":
+				$("options").innerHTML = "";
+				$("text").hidden = true;
+				$("status").textContent += " The answer was \"" + msg.answer + "\".";
+				break;
+			case "finished":
+				$("question").textContent = "The quiz has finished!";
+				break;
+			case "error":
+				$("status").textContent = msg.error;
+				break;
+			}
+		}
+	</script>
+</body>
+</html>
+`
+
+var roomTemplate = template.Must(template.New("RoomTemplate").Parse(ROOM_TEMPLATE))
@@ -0,0 +1,297 @@
+package quiz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newTestRoom(t *testing.T, clock Clock) *Room {
+
+	q := newTestQuiz(t, "5+5,10\n1+1,2\n")
+	room := NewRoom("ABCD", q, clock)
+	room.QuestionTime = 20 * time.Second
+
+	return room
+}
+
+// nextEvent returns the next event of the specified type from
+// the events, skipping any others.
+func nextEvent(t *testing.T, events <-chan RoomEvent, eventType string) RoomEvent {
+
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed waiting for %q", eventType)
+			}
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %q", eventType)
+		}
+	}
+}
+
+func TestRoomJoin(t *testing.T) {
+
+	room := newTestRoom(t, newFakeClock())
+
+	if _, err := room.Join("alice"); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if _, err := room.Join(" alice "); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("joining twice: got error %v, want %v", err, ErrNameTaken)
+	}
+	if _, err := room.Join(""); err == nil {
+		t.Error("joining without a name: got no error")
+	}
+
+	room.Next()
+	if _, err := room.Join("bob"); !errors.Is(err, ErrRoomStarted) {
+		t.Errorf("joining once started: got error %v, want %v", err, ErrRoomStarted)
+	}
+}
+
+func TestRoomScoresBySpeed(t *testing.T) {
+
+	clock := newFakeClock()
+	room := newTestRoom(t, clock)
+	alice, _ := room.Join("alice")
+	bob, _ := room.Join("bob")
+
+	// Each question is put to every player.
+	if !room.Next() {
+		t.Fatal("Next: no question was asked")
+	}
+	for _, p := range []*Player{alice, bob} {
+		event := nextEvent(t, p.Events(), "question")
+		if q := event.Question; q.Number != 1 || q.Total != 2 || q.Question != "5+5" || q.TimeLimit != 20 {
+			t.Errorf("%s was asked %+v, want question 1 of 2 \"5+5\" in 20s", p.Name, *q)
+		}
+	}
+
+	// A correct answer earns ROOM_POINTS at once, falling to half
+	// as the question's time runs out.
+	answer, err := room.Answer("alice", "10")
+	if err != nil || !answer.Correct || answer.Points != ROOM_POINTS {
+		t.Errorf("alice's answer: got %+v, %v, want %d points", answer, err, ROOM_POINTS)
+	}
+	if _, err := room.Answer("alice", "10"); !errors.Is(err, ErrAlreadyAnswered) {
+		t.Errorf("answering twice: got error %v, want %v", err, ErrAlreadyAnswered)
+	}
+	clock.Advance(10 * time.Second)
+	answer, err = room.Answer("bob", "10")
+	if err != nil || !answer.Correct || answer.Points != 750 {
+		t.Errorf("bob's answer: got %+v, %v, want 750 points", answer, err)
+	}
+
+	// The question closes once everyone has answered.
+	if reveal := nextEvent(t, alice.Events(), "reveal"); reveal.Answer != "10" {
+		t.Errorf("revealed %q, want \"10\"", reveal.Answer)
+	}
+	if _, err := room.Answer("bob", "10"); !errors.Is(err, ErrNotAsking) {
+		t.Errorf("answering a closed question: got error %v, want %v", err, ErrNotAsking)
+	}
+
+	room.Next()
+	nextEvent(t, bob.Events(), "question")
+	clock.Advance(2 * time.Second)
+	if answer, _ := room.Answer("bob", "2"); answer.Points != 950 {
+		t.Errorf("bob's second answer: got %v points, want 950", answer.Points)
+	}
+	if answer, _ := room.Answer("alice", "3"); answer.Correct || answer.Points != 0 {
+		t.Errorf("alice's wrong answer: got %+v, want no points", answer)
+	}
+
+	if room.Next() {
+		t.Fatal("Next: asked a question after the last")
+	}
+	finished := nextEvent(t, alice.Events(), "finished")
+	want := []Standing{
+		{Name: "bob", Score: 1700, Correct: 2},
+		{Name: "alice", Score: 1000, Correct: 1},
+	}
+	if !equalStandings(finished.Leaderboard, want) {
+		t.Errorf("got the leaderboard %+v, want %+v", finished.Leaderboard, want)
+	}
+	if !room.Finished() {
+		t.Error("the room has not finished")
+	}
+}
+
+func TestRoomQuestionTimeout(t *testing.T) {
+
+	clock := newFakeClock()
+	room := newTestRoom(t, clock)
+	alice, _ := room.Join("alice")
+	room.Join("bob")
+	events := room.Watch()
+
+	room.Next()
+	room.Answer("alice", "10")
+	clock.Advance(room.QuestionTime)
+
+	reveal := nextEvent(t, events, "reveal")
+	if reveal.Answer != "10" || reveal.Leaderboard[0].Name != "alice" {
+		t.Errorf("got the reveal %+v, want the answer 10 with alice leading", reveal)
+	}
+	if _, err := room.Answer("bob", "10"); !errors.Is(err, ErrNotAsking) {
+		t.Errorf("answering once the time ran out: got error %v, want %v", err, ErrNotAsking)
+	}
+
+	room.Leave("alice")
+	for range alice.Events() {
+		// The events are closed once alice has left.
+	}
+	if board := room.Leaderboard(); len(board) != 1 || board[0].Name != "bob" {
+		t.Errorf("got the leaderboard %+v once alice left, want only bob", board)
+	}
+}
+
+func TestRoomWatchClosesOnFinish(t *testing.T) {
+
+	room := newTestRoom(t, newFakeClock())
+	room.Join("alice")
+	events := room.Watch()
+
+	room.Next()
+	room.End()
+
+	// The final leaderboard is the last event before the events
+	// are closed.
+	var last RoomEvent
+	for event := range events {
+		last = event
+	}
+	if last.Type != "finished" || len(last.Leaderboard) != 1 {
+		t.Errorf("got the last event %+v, want the final leaderboard", last)
+	}
+
+	// Watching a finished Room gets no events.
+	if _, ok := <-room.Watch(); ok {
+		t.Error("got an event watching a finished room")
+	}
+
+	// Nor are the events closed again by anything after the end.
+	room.End()
+	room.Leave("alice")
+}
+
+// roomReply is any message a RoomServer sends to a player.
+type roomReply struct {
+	Type        string        `json:"type"`
+	Question    *QuestionView `json:"question"`
+	Answer      string        `json:"answer"`
+	Leaderboard []Standing    `json:"leaderboard"`
+	Correct     bool          `json:"correct"`
+	Points      float64       `json:"points"`
+	Error       string        `json:"error"`
+}
+
+// readReply returns the next message of the specified type from
+// the connection, skipping any others.
+func readReply(t *testing.T, conn *websocket.Conn, replyType string) roomReply {
+
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var reply roomReply
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("waiting for %q: %v", replyType, err)
+		}
+		if reply.Type == replyType {
+			return reply
+		}
+	}
+}
+
+func dialRoom(t *testing.T, server *httptest.Server, code string, name string) *websocket.Conn {
+
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?code=" + code + "&name=" + name
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dialing %s: %v", url, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestRoomServer(t *testing.T) {
+
+	clock := newFakeClock()
+	room := newTestRoom(t, clock)
+	events := room.Watch()
+
+	rooms := NewRoomServer()
+	rooms.Add(room)
+	server := httptest.NewServer(rooms)
+	defer server.Close()
+
+	// Rooms are joined by their code, in any case.
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?code=WXYZ&name=alice"
+	if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("joining an unknown room: got error %v, want the status %d", err, http.StatusNotFound)
+	}
+	alice := dialRoom(t, server, "abcd", "alice")
+	nextEvent(t, events, "joined")
+	bob := dialRoom(t, server, "ABCD", "bob")
+	nextEvent(t, events, "joined")
+
+	taken := dialRoom(t, server, "ABCD", "bob")
+	if reply := readReply(t, taken, "error"); reply.Error != ErrNameTaken.Error() {
+		t.Errorf("joining with a taken name: got %q, want %q", reply.Error, ErrNameTaken)
+	}
+
+	for number, question := range []string{"5+5", "1+1"} {
+		room.Next()
+		for _, conn := range []*websocket.Conn{alice, bob} {
+			reply := readReply(t, conn, "question")
+			if reply.Question.Number != number+1 || reply.Question.Question != question {
+				t.Errorf("got question %d %q, want %d %q",
+					reply.Question.Number, reply.Question.Question, number+1, question)
+			}
+		}
+
+		alice.WriteJSON(map[string]string{"type": "answer", "response": "10"})
+		readReply(t, alice, "result")
+		clock.Advance(4 * time.Second)
+		bob.WriteJSON(map[string]string{"type": "answer", "response": "2"})
+		readReply(t, bob, "result")
+		nextEvent(t, events, "reveal")
+	}
+
+	room.Next()
+	finished := readReply(t, bob, "finished")
+	want := []Standing{
+		{Name: "alice", Score: 1000, Correct: 1},
+		{Name: "bob", Score: 900, Correct: 1},
+	}
+	if !equalStandings(finished.Leaderboard, want) {
+		t.Errorf("got the leaderboard %+v, want %+v", finished.Leaderboard, want)
+	}
+}
+
+func equalStandings(a, b []Standing) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,571 @@
+package quiz
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SESSION_TTL is how long a Session may go unused before the
+// Server forgets it.
+const SESSION_TTL = time.Hour
+
+// Server is an http.Handler that gives a Quiz over HTTP, both as
+// HTML pages and as a JSON API. Each visitor takes the quiz in a
+// Session of their own, whose time limits are enforced by the
+// server rather than trusted to the client.
+//
+// The JSON API is made up of:
+//
+//	POST /api/sessions                 start a Session
+//	GET  /api/sessions/{id}/question   fetch the current question
+//	POST /api/sessions/{id}/answer     submit {"response": "..."}
+//	GET  /api/sessions/{id}/result     fetch the result so far
+//
+// The images and audio clips of the questions are served under
+// /assets/.
+type Server struct {
+	Quiz  *Quiz
+	Clock Clock
+
+	// Shuffle gives each Session its own order of questions.
+	Shuffle bool
+
+	// Assets holds the media the Problems refer to by path, e.g.
+	// the directory of the problem source. See OpenAssets. Only
+	// files that some Problem refers to are served from it.
+	Assets fs.FS
+
+	mu       sync.Mutex
+	sessions map[string]*serverSession
+}
+
+// serverSession is a Session along with the lock serializing the
+// requests made to it.
+type serverSession struct {
+	mu       sync.Mutex
+	session  *Session
+	lastUsed time.Time
+}
+
+// NewServer returns a Server that gives the Quiz, timed by the
+// clock.
+func NewServer(q *Quiz, clock Clock) *Server {
+	return &Server{Quiz: q, Clock: clock}
+}
+
+// ServeHTTP routes a request to the HTML pages or the JSON API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	path := r.URL.Path
+
+	switch {
+
+	case path == "/":
+		s.serveIntro(w, r)
+
+	case path == "/start":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id := s.start()
+		http.Redirect(w, r, "/quiz/"+id, http.StatusSeeOther)
+
+	case strings.HasPrefix(path, "/quiz/"):
+		s.servePage(w, r, strings.TrimPrefix(path, "/quiz/"))
+
+	case strings.HasPrefix(path, "/assets/"):
+		s.serveAsset(w, r, strings.TrimPrefix(path, "/assets/"))
+
+	case path == "/api/sessions":
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id := s.start()
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"id":         id,
+			"total":      s.Quiz.Total(),
+			"time_limit": s.Quiz.TimeLimit.Seconds(),
+		})
+
+	case strings.HasPrefix(path, "/api/sessions/"):
+		parts := strings.Split(strings.TrimPrefix(path, "/api/sessions/"), "/")
+		if len(parts) != 2 {
+			writeJSONError(w, http.StatusNotFound, "not found")
+			return
+		}
+		s.serveAPI(w, r, parts[0], parts[1])
+
+	default:
+		http.NotFound(w, r)
+
+	}
+}
+
+// start begins a new Session and returns its id. Sessions that
+// have gone unused for longer than SESSION_TTL are forgotten.
+func (s *Server) start() string {
+
+	q := *s.Quiz
+	if s.Shuffle {
+		q.Problems = Shuffle(q.Problems, mathrand.New(mathrand.NewSource(s.Clock.Now().UnixNano())))
+	}
+
+	id := newSessionID()
+	now := s.Clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions == nil {
+		s.sessions = map[string]*serverSession{}
+	}
+	for key, ss := range s.sessions {
+		ss.mu.Lock()
+		if now.Sub(ss.lastUsed) > SESSION_TTL {
+			delete(s.sessions, key)
+		}
+		ss.mu.Unlock()
+	}
+	s.sessions[id] = &serverSession{session: q.Start(s.Clock), lastUsed: now}
+
+	return id
+}
+
+// lookup returns the Session with the specified id, locked for
+// the duration of a request. The caller must unlock it.
+func (s *Server) lookup(id string) (*serverSession, bool) {
+
+	s.mu.Lock()
+	ss, ok := s.sessions[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	ss.mu.Lock()
+	ss.lastUsed = s.Clock.Now()
+
+	return ss, true
+}
+
+// QuestionView is a question as it is sent to a client, e.g. by
+// the JSON API. Times are given in seconds.
+type QuestionView struct {
+	Number            int          `json:"number"`
+	Total             int          `json:"total"`
+	Question          string       `json:"question"`
+	Type              QuestionType `json:"type"`
+	Hint              string       `json:"hint,omitempty"`
+	Options           []OptionView `json:"options,omitempty"`
+	Image             string       `json:"image,omitempty"`
+	Audio             string       `json:"audio,omitempty"`
+	Code              string       `json:"code,omitempty"`
+	Language          string       `json:"language,omitempty"`
+	TimeLimit         float64      `json:"time_limit,omitempty"`
+	QuestionRemaining float64      `json:"question_remaining,omitempty"`
+	Remaining         float64      `json:"remaining,omitempty"`
+}
+
+// OptionView is a lettered option of a QuestionView.
+type OptionView struct {
+	Letter string `json:"letter"`
+	Text   string `json:"text"`
+}
+
+// answerJSON is an Answer as it is sent by the JSON API.
+type answerJSON struct {
+	Question string  `json:"question"`
+	Response string  `json:"response"`
+	Expected string  `json:"expected"`
+	Correct  bool    `json:"correct"`
+	Credit   float64 `json:"credit"`
+	Points   float64 `json:"points"`
+	TimedOut bool    `json:"timed_out"`
+	Latency  float64 `json:"latency"`
+}
+
+// resultJSON is a Result as it is sent by the JSON API.
+type resultJSON struct {
+	Finished bool         `json:"finished"`
+	Score    float64      `json:"score"`
+	MaxScore float64      `json:"max_score"`
+	Correct  int          `json:"correct"`
+	Total    int          `json:"total"`
+	Answers  []answerJSON `json:"answers"`
+}
+
+// serveAPI handles a JSON API request for the specified action
+// on the Session with the specified id.
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, id string, action string) {
+
+	ss, ok := s.lookup(id)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown session")
+		return
+	}
+	defer ss.mu.Unlock()
+	session := ss.session
+
+	switch action {
+
+	case "question":
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		prompt, ok := session.Next()
+		if !ok {
+			writeJSONError(w, http.StatusGone, ErrFinished.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.sessionQuestionView(session, prompt))
+
+	case "answer":
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var body struct {
+			Response string `json:"response"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		answer, err := session.Submit(body.Response)
+		switch err {
+		case nil:
+			writeJSON(w, http.StatusOK, newAnswerJSON(answer))
+		case ErrFinished:
+			writeJSONError(w, http.StatusGone, err.Error())
+		default:
+			writeJSONError(w, http.StatusConflict, err.Error())
+		}
+
+	case "result":
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, newResultJSON(session))
+
+	default:
+		writeJSONError(w, http.StatusNotFound, "not found")
+
+	}
+}
+
+func (s *Server) sessionQuestionView(session *Session, prompt Prompt) QuestionView {
+
+	now := s.Clock.Now()
+	q := NewQuestionView(session.Number(), s.Quiz.Total(), prompt)
+	if deadline := session.QuestionDeadline(); !deadline.IsZero() {
+		q.QuestionRemaining = deadline.Sub(now).Seconds()
+	}
+	if deadline := session.Deadline(); !deadline.IsZero() {
+		q.Remaining = deadline.Sub(now).Seconds()
+	}
+
+	return q
+}
+
+// NewQuestionView returns the view of a prompt that is the
+// specified question number of total. Media kept alongside the
+// problem source are referred to by their URL under /assets/.
+func NewQuestionView(number int, total int, prompt Prompt) QuestionView {
+
+	q := QuestionView{
+		Number:    number,
+		Total:     total,
+		Question:  prompt.Problem.Question,
+		Type:      prompt.Problem.Type,
+		Hint:      prompt.Hint(),
+		Image:     assetURL(prompt.Problem.Image),
+		Audio:     assetURL(prompt.Problem.Audio),
+		Code:      prompt.Problem.Code,
+		Language:  prompt.Problem.Language,
+		TimeLimit: prompt.Problem.TimeLimit.Seconds(),
+	}
+	if q.Type == "" {
+		q.Type = TypeText
+	}
+	for i, option := range prompt.Options {
+		q.Options = append(q.Options, OptionView{Letter(i), option})
+	}
+
+	return q
+}
+
+// assetURL returns the URL a Problem's media is fetched from.
+func assetURL(ref string) string {
+
+	if ref == "" || isURL(ref) {
+		return ref
+	}
+
+	return "/assets/" + (&url.URL{Path: ref}).EscapedPath()
+}
+
+// serveAsset serves the media file at the specified path, if a
+// Problem refers to it. Nothing else in the Assets is served, so
+// that e.g. the problem source itself cannot be fetched.
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request, name string) {
+
+	referenced := false
+	for _, p := range s.Quiz.Problems {
+		for _, asset := range p.Assets() {
+			referenced = referenced || asset == name
+		}
+	}
+	if !referenced || s.Assets == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := fs.ReadFile(s.Assets, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var modified time.Time
+	if info, err := fs.Stat(s.Assets, name); err == nil {
+		modified = info.ModTime()
+	}
+	http.ServeContent(w, r, name, modified, bytes.NewReader(data))
+}
+
+func newAnswerJSON(a Answer) answerJSON {
+	return answerJSON{
+		Question: a.Problem.Question,
+		Response: a.Response,
+		Expected: a.Problem.Answer,
+		Correct:  a.Correct,
+		Credit:   a.Credit,
+		Points:   a.Points,
+		TimedOut: a.TimedOut,
+		Latency:  a.Latency.Seconds(),
+	}
+}
+
+func newResultJSON(session *Session) resultJSON {
+
+	result := session.Result()
+	r := resultJSON{
+		Finished: session.Finished(),
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Correct:  result.Correct,
+		Total:    result.Total,
+		Answers:  []answerJSON{},
+	}
+	for _, a := range result.Answers {
+		r.Answers = append(r.Answers, newAnswerJSON(a))
+	}
+
+	return r
+}
+
+// writeJSON writes the value as a JSON response with the
+// specified status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes a JSON error response of the form
+// {"error": "..."} with the specified status code.
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// newSessionID returns a random, unguessable Session id.
+func newSessionID() string {
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// pageData is the data rendered by pageTemplate.
+type pageData struct {
+	ID       string
+	Number   int
+	Total    int
+	Prompt   Prompt
+	Options  []OptionView
+	Image    string
+	Audio    string
+	Multi    bool
+	Text     bool
+	Refresh  int
+	Last     *Answer
+	Finished bool
+	Result   Result
+	Limit    time.Duration
+}
+
+// servePage handles the HTML page of the Session with the
+// specified id. A POST submits the answer to the current
+// question and redirects back to the page.
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request, id string) {
+
+	ss, ok := s.lookup(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	defer ss.mu.Unlock()
+	session := ss.session
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		session.Submit(strings.Join(r.Form["response"], ","))
+		http.Redirect(w, r, "/quiz/"+id, http.StatusSeeOther)
+		return
+	}
+
+	data := pageData{ID: id, Total: s.Quiz.Total()}
+	result := session.Result()
+	if n := len(result.Answers); n > 0 {
+		data.Last = &result.Answers[n-1]
+	}
+
+	prompt, ok := session.Next()
+	if !ok {
+		data.Finished = true
+		data.Result = session.Result()
+		renderPage(w, data)
+		return
+	}
+
+	data.Number = session.Number()
+	data.Prompt = prompt
+	data.Limit = prompt.Problem.TimeLimit
+	data.Image = assetURL(prompt.Problem.Image)
+	data.Audio = assetURL(prompt.Problem.Audio)
+	data.Multi = prompt.Problem.Type == TypeMultiSelect
+	data.Text = len(prompt.Options) == 0
+	for i, option := range prompt.Options {
+		data.Options = append(data.Options, OptionView{Letter(i), option})
+	}
+
+	// The page is refreshed once a time limit expires so that the
+	// server's decision is shown without a submission.
+	now := s.Clock.Now()
+	for _, deadline := range []time.Time{session.QuestionDeadline(), session.Deadline()} {
+		if deadline.IsZero() {
+			continue
+		}
+		seconds := int(math.Max(1, math.Ceil(deadline.Sub(now).Seconds())))
+		if data.Refresh == 0 || seconds < data.Refresh {
+			data.Refresh = seconds
+		}
+	}
+
+	renderPage(w, data)
+}
+
+// serveIntro handles the page inviting the user to start a quiz.
+func (s *Server) serveIntro(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	introTemplate.Execute(w, s.Quiz)
+}
+
+// renderPage renders the page of a Session.
+func renderPage(w http.ResponseWriter, data pageData) {
+
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+const INTRO_TEMPLATE = `
+<!DOCTYPE html>
+<html>
+<head><title>Quiz</title></head>
+<body>
+	<h1>You are about to take a quiz.</h1>
+	<p>There are {{.Total}} questions.</p>
+	{{if .TimeLimit}}<p>The timer is set to {{.TimeLimit}}.</p>{{end}}
+	<form method="post" action="/start">
+		<button type="submit">Begin</button>
+	</form>
+</body>
+</html>
+`
+
+const PAGE_TEMPLATE = `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Quiz</title>
+	{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+</head>
+<body>
+	{{with .Last}}
+		<p>
+			{{if .TimedOut}}Time's up!
+			{{else if .Correct}}Correct!
+			{{else}}Incorrect. The answer was "{{.Problem.Answer}}".
+			{{end}}
+		</p>
+	{{end}}
+	{{if .Finished}}
+		<h1>You scored {{.Result.Score}} out of {{.Result.MaxScore}} points.</h1>
+		<p>{{.Result.Correct}} of {{.Result.Total}} correct.</p>
+		<a href="/" class="button">Take the quiz again</a>
+	{{else}}
+		<h1>{{.Number}}. {{.Prompt.Problem.Question}}?</h1>
+		{{if .Image}}<p><img src="{{.Image}}" alt="" style="max-width: 100%"></p>{{end}}
+		{{if .Audio}}<p><audio controls src="{{.Audio}}"></audio></p>{{end}}
+		{{with .Prompt.Problem}}{{if .Code}}
+			<pre><code{{if .Language}} class="language-{{.Language}}"{{end}}>{{.Code}}</code></pre>
+		{{end}}{{end}}
+		{{if .Limit}}<p>You have {{.Limit}} to answer.</p>{{end}}
+		<form method="post" action="/quiz/{{.ID}}">
+			{{if .Text}}
+				<input type="text" name="response" autofocus>
+			{{else}}
+				{{$multi := .Multi}}
+				{{range .Options}}
+					<label>
+						<input type="{{if $multi}}checkbox{{else}}radio{{end}}" name="response" value="{{.Letter}}">
+						{{.Letter}}) {{.Text}}
+					</label>
+					<br>
+				{{end}}
+			{{end}}
+			<button type="submit">Answer</button>
+		</form>
+		<p>Question {{.Number}} of {{.Total}}</p>
+	{{end}}
+</body>
+</html>
+`
+
+var introTemplate = template.Must(template.New("IntroTemplate").Parse(INTRO_TEMPLATE))
+var pageTemplate = template.Must(template.New("PageTemplate").Parse(PAGE_TEMPLATE))
@@ -0,0 +1,33 @@
+package quiz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeIntroCount(t *testing.T) {
+
+	q := newTestQuiz(t, "1+1,2\n2+2,4\n3+3,6\n")
+	server := NewServer(q, newFakeClock())
+
+	tests := []struct {
+		questions int
+		want      string
+	}{
+		{0, "There are 3 questions."},
+		{2, "There are 2 questions."},
+		{5, "There are 3 questions."},
+	}
+
+	for _, test := range tests {
+		q.Questions = test.questions
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if !strings.Contains(rec.Body.String(), test.want) {
+			t.Errorf("%d questions asked: got\n%s\nwant %q", test.questions, rec.Body.String(), test.want)
+		}
+	}
+}
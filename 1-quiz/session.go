@@ -0,0 +1,368 @@
+package quiz
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrFinished is returned when answering a Session that has
+// already finished.
+var ErrFinished = errors.New("quiz has finished")
+
+// ErrNoQuestion is returned when answering a Session before the
+// next question has been asked.
+var ErrNoQuestion = errors.New("no question is being asked")
+
+// Session is a Quiz in progress. It keeps track of the current
+// question and enforces the time limits by the clock, leaving
+// the presentation of questions to its caller.
+type Session struct {
+	quiz   *Quiz
+	clock  Clock
+	rng    *rand.Rand
+	result Result
+
+	remaining []Problem
+	questions int
+	number    int
+	current   *Prompt
+	asked     time.Time
+	deadline  time.Time
+	finished  bool
+
+	// paused is when the clock was stopped, or the zero time if
+	// it is running.
+	paused time.Time
+
+	// timedOut is the Answer recorded when the current question
+	// expired, until the next question is asked.
+	timedOut *Answer
+}
+
+// Start begins a Session of the Quiz. The quiz's TimeLimit
+// starts counting down immediately.
+func (q *Quiz) Start(clock Clock) *Session {
+
+	s := &Session{
+		quiz:      q,
+		clock:     clock,
+		result:    newSessionResult(q.Problems, q.Questions),
+		remaining: append([]Problem(nil), q.Problems...),
+		questions: q.Questions,
+	}
+
+	if s.result.Seed = q.Seed; s.result.Seed == 0 {
+		s.result.Seed = clock.Now().UnixNano()
+	}
+	s.rng = rand.New(rand.NewSource(s.result.Seed))
+
+	s.result.Started = clock.Now()
+	if q.TimeLimit > 0 {
+		s.deadline = s.result.Started.Add(q.TimeLimit)
+	}
+
+	return s
+}
+
+// SavedSession is the state of an unfinished Session, which may
+// be serialized, e.g. as JSON, and later resumed.
+type SavedSession struct {
+	// Remaining are the Problems yet to be asked, including any
+	// question that was being asked when the Session was saved.
+	Remaining []Problem `json:"remaining"`
+
+	// Questions is how many Problems are asked in total, or zero
+	// for all of them.
+	Questions int `json:"questions,omitempty"`
+
+	// Number is how many questions had been answered.
+	Number int `json:"number"`
+
+	// TimeLeft is what remained of the quiz's TimeLimit, or zero
+	// if it had none.
+	TimeLeft time.Duration `json:"time_left,omitempty"`
+
+	Result Result `json:"result"`
+}
+
+// Save returns the state of the Session, to be resumed later by
+// Quiz.Resume. The question being asked, if any, is asked again
+// on resuming.
+func (s *Session) Save() SavedSession {
+
+	s.expire()
+	saved := SavedSession{
+		Remaining: append([]Problem(nil), s.remaining...),
+		Questions: s.questions,
+		Number:    s.number,
+		Result:    s.result,
+	}
+	saved.Result.Answers = append([]Answer(nil), s.result.Answers...)
+
+	if s.current != nil {
+		saved.Remaining = append([]Problem{s.current.Problem}, saved.Remaining...)
+		saved.Number--
+	}
+
+	if !s.deadline.IsZero() {
+		now := s.clock.Now()
+		if s.Paused() {
+			now = s.paused
+		}
+		saved.TimeLeft = s.deadline.Sub(now)
+	}
+
+	return saved
+}
+
+// Resume continues a saved Session under the Quiz's Policy. The
+// Problems still to be asked and the time left are taken from
+// the SavedSession rather than the Quiz.
+func (q *Quiz) Resume(saved SavedSession, clock Clock) *Session {
+
+	s := &Session{
+		quiz:      q,
+		clock:     clock,
+		result:    saved.Result,
+		remaining: append([]Problem(nil), saved.Remaining...),
+		questions: saved.Questions,
+		number:    saved.Number,
+	}
+	s.rng = rand.New(rand.NewSource(saved.Result.Seed + int64(saved.Number)))
+
+	if saved.TimeLeft > 0 {
+		s.deadline = clock.Now().Add(saved.TimeLeft)
+	}
+
+	return s
+}
+
+// Next returns the question currently being asked, asking the
+// next one if there is none. It returns false once the Session
+// has finished.
+func (s *Session) Next() (Prompt, bool) {
+
+	s.expire()
+	if s.finished {
+		return Prompt{}, false
+	}
+
+	if s.current == nil {
+		i := s.choose()
+		if i < 0 {
+			s.finished = true
+			return Prompt{}, false
+		}
+
+		prompt := s.remaining[i].Prompt(s.rng)
+		s.remaining = append(s.remaining[:i], s.remaining[i+1:]...)
+		s.current = &prompt
+		s.timedOut = nil
+		s.asked = s.clock.Now()
+		s.number++
+	}
+
+	return *s.current, true
+}
+
+// Number returns the position of the current question in the
+// quiz, starting from 1.
+func (s *Session) Number() int {
+	return s.number
+}
+
+// Submit grades a response to the current question and records
+// the Answer.
+//
+// If the question's TimeLimit was exceeded before the response
+// was submitted, the response is ignored and the Answer that was
+// recorded as TimedOut is returned instead.
+func (s *Session) Submit(response string) (Answer, error) {
+	return s.submit(response, nil)
+}
+
+// submit is Submit with the Flags raised while the response was
+// read. The Quiz's Proctor may raise further Flags.
+func (s *Session) submit(response string, flags []Flag) (Answer, error) {
+
+	s.expire()
+	if s.timedOut != nil {
+		answer := *s.timedOut
+		s.timedOut = nil
+		return answer, nil
+	}
+	if s.finished {
+		return Answer{}, ErrFinished
+	}
+	if s.current == nil {
+		return Answer{}, ErrNoQuestion
+	}
+
+	prompt := *s.current
+	answer := Answer{
+		Problem:  prompt.Problem,
+		Response: response,
+		Credit:   prompt.Grade(response),
+		Latency:  s.clock.Now().Sub(s.asked),
+		Flags:    flags,
+	}
+	if s.quiz.Proctor != nil && s.quiz.Proctor.TooFast(prompt, answer) {
+		answer.Flags = append(answer.Flags, FlagFast)
+	}
+
+	return s.record(answer), nil
+}
+
+// TimeOut marks the current question as unanswered within its
+// TimeLimit and moves on to the next question.
+func (s *Session) TimeOut() {
+
+	if s.current == nil || s.finished {
+		return
+	}
+
+	latency := s.clock.Now().Sub(s.asked)
+	if limit := s.current.Problem.TimeLimit; limit > 0 && latency > limit {
+		latency = limit
+	}
+
+	answer := s.record(Answer{
+		Problem:  s.current.Problem,
+		TimedOut: true,
+		Latency:  latency,
+	})
+	s.timedOut = &answer
+}
+
+// End finishes the Session, leaving any remaining questions
+// unanswered.
+func (s *Session) End() {
+	s.finished = true
+}
+
+// Finished reports whether the Session has finished.
+func (s *Session) Finished() bool {
+
+	s.expire()
+
+	return s.finished
+}
+
+// Result returns the outcome of the Session so far.
+func (s *Session) Result() Result {
+	return s.result
+}
+
+// Pause stops the clock, so that neither the quiz's nor the
+// current question's TimeLimit runs down until Resume.
+func (s *Session) Pause() {
+
+	s.expire()
+	if s.finished || !s.paused.IsZero() {
+		return
+	}
+
+	s.paused = s.clock.Now()
+}
+
+// Resume restarts the clock after Pause, extending the deadlines
+// by the time spent paused.
+func (s *Session) Resume() {
+
+	if s.paused.IsZero() {
+		return
+	}
+
+	d := s.clock.Now().Sub(s.paused)
+	if !s.deadline.IsZero() {
+		s.deadline = s.deadline.Add(d)
+	}
+	s.asked = s.asked.Add(d)
+	s.paused = time.Time{}
+}
+
+// Paused reports whether the clock is stopped.
+func (s *Session) Paused() bool {
+	return !s.paused.IsZero()
+}
+
+// Deadline returns when the quiz's TimeLimit expires, or the
+// zero time if it never does.
+func (s *Session) Deadline() time.Time {
+	return s.deadline
+}
+
+// QuestionDeadline returns when the current question's
+// TimeLimit expires, or the zero time if it never does.
+func (s *Session) QuestionDeadline() time.Time {
+
+	if s.current == nil || s.current.Problem.TimeLimit == 0 {
+		return time.Time{}
+	}
+
+	return s.asked.Add(s.current.Problem.TimeLimit)
+}
+
+// choose returns the index within the remaining Problems of the
+// next to ask, or -1 once none are left to ask.
+//
+// If the quiz ends with Problems still remaining, they were
+// never going to be asked, so the Result is totalled over just
+// the Problems that were.
+func (s *Session) choose() int {
+
+	i := -1
+	switch {
+	case len(s.remaining) == 0:
+		return -1
+	case s.questions > 0 && s.number >= s.questions:
+	case s.quiz.Policy == nil:
+		i = 0
+	default:
+		i = s.quiz.Policy.Choose(s.remaining, s.result.Answers)
+	}
+
+	if i < 0 || i >= len(s.remaining) {
+		s.result.Total = len(s.result.Answers)
+		s.result.MaxScore = 0
+		for _, a := range s.result.Answers {
+			s.result.MaxScore += a.Problem.Value()
+		}
+		return -1
+	}
+
+	return i
+}
+
+// expire enforces the time limits as of the current time.
+func (s *Session) expire() {
+
+	if s.finished || s.Paused() {
+		return
+	}
+
+	now := s.clock.Now()
+	if !s.deadline.IsZero() && !now.Before(s.deadline) {
+		s.finished = true
+		return
+	}
+
+	if deadline := s.QuestionDeadline(); !deadline.IsZero() && !now.Before(deadline) {
+		s.TimeOut()
+	}
+}
+
+// record adds an Answer to the current question to the Result,
+// linking it into the hash chain if the Quiz has a Key, and
+// returns it as it was scored.
+func (s *Session) record(a Answer) Answer {
+
+	s.result.Add(a)
+	s.current = nil
+	if s.quiz.Key != nil {
+		chainAnswer(s.result.Answers, s.quiz.Key)
+	}
+
+	return s.result.Answers[len(s.result.Answers)-1]
+}
@@ -0,0 +1,70 @@
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Shuffle returns a copy of the problems but shuffled
+// psuedo-randomly using the specified source.
+func Shuffle(problems []Problem, rng *rand.Rand) []Problem {
+
+	data := make([]Problem, len(problems))
+	copy(data, problems)
+
+	rng.Shuffle(
+		len(data),
+		func(i int, j int) {
+			data[i], data[j] = data[j], data[i]
+		},
+	)
+
+	return data
+}
+
+// ShuffleWithinCategories returns a copy of the problems with
+// each Category kept together, in the order it first appears,
+// but with the problems within it shuffled using the specified
+// source.
+func ShuffleWithinCategories(problems []Problem, rng *rand.Rand) []Problem {
+
+	var order []string
+	categories := map[string][]Problem{}
+	for _, p := range problems {
+		if _, ok := categories[p.Category]; !ok {
+			order = append(order, p.Category)
+		}
+		categories[p.Category] = append(categories[p.Category], p)
+	}
+
+	data := make([]Problem, 0, len(problems))
+	for _, category := range order {
+		data = append(data, Shuffle(categories[category], rng)...)
+	}
+
+	return data
+}
+
+// Sample returns n of the problems chosen psuedo-randomly using
+// the specified source. The problems keep their relative order.
+// If n is not less than the number of problems, a copy of all
+// of them is returned.
+func Sample(problems []Problem, n int, rng *rand.Rand) []Problem {
+
+	if n >= len(problems) {
+		return append([]Problem(nil), problems...)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	indices := rng.Perm(len(problems))[:n]
+	sort.Ints(indices)
+
+	data := make([]Problem, n)
+	for i, index := range indices {
+		data[i] = problems[index]
+	}
+
+	return data
+}
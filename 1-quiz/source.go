@@ -0,0 +1,138 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Loader parses Problems from the specified reader. The name
+// identifies the source, e.g. a filepath, in any errors.
+//
+// Malformed records are reported together as ParseErrors
+// alongside the Problems that could be parsed.
+type Loader func(name string, r io.Reader) ([]Problem, error)
+
+var loaders = map[string]Loader{}
+var extensions = map[string]string{}
+
+// RegisterFormat makes a Loader available under the specified
+// format name and file extensions, e.g. "csv" and ".csv".
+func RegisterFormat(format string, loader Loader, exts ...string) {
+
+	loaders[format] = loader
+	for _, ext := range exts {
+		extensions[strings.ToLower(ext)] = format
+	}
+}
+
+// Formats returns the names of all registered formats.
+func Formats() []string {
+
+	var formats []string
+	for format := range loaders {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// FormatFromPath returns the format registered for the file
+// extension of the specified path.
+func FormatFromPath(path string) (string, error) {
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if format, ok := extensions[ext]; ok {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("%s: unknown problem file extension %q", path, ext)
+}
+
+// Load parses Problems from the specified reader using the
+// Loader registered for format.
+func Load(name string, r io.Reader, format string) ([]Problem, error) {
+
+	loader, ok := loaders[format]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown problem format %q (expected one of %s)",
+			format, strings.Join(Formats(), ", "),
+		)
+	}
+
+	return loader(name, r)
+}
+
+// LoadFile parses Problems from the file at the specified
+// path. If format is empty it is chosen by file extension.
+func LoadFile(path string, format string) ([]Problem, error) {
+
+	if format == "" {
+		var err error
+		if format, err = FormatFromPath(path); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(path, f, format)
+}
+
+// ParseError reports a malformed record within a problem
+// source. Line is zero if the position is unknown.
+type ParseError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+
+	file := e.File
+	if file == "" {
+		file = "<input>"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", file, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", file, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors is a list of every malformed record found
+// within a problem source.
+type ParseErrors []*ParseError
+
+func (errs ParseErrors) Error() string {
+
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// errorOrNil returns errs as an error only if it is non-empty.
+func (errs ParseErrors) errorOrNil() error {
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
@@ -0,0 +1,131 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterFormat("csv", LoadCSV, ".csv")
+	RegisterFormat("tsv", LoadTSV, ".tsv", ".tab")
+}
+
+// LoadCSV is the Loader for comma-separated problem sources.
+//
+// CSV files may have questions with commas in them, e.g.
+// `"what 2+2, sir?",4` is a valid row.
+func LoadCSV(name string, r io.Reader) ([]Problem, error) {
+
+	csvReader := csv.NewReader(r)
+
+	return loadDelimited(name, csvReader)
+}
+
+// LoadTSV is the Loader for tab-separated problem sources.
+// A quote within a field is kept as it is, though a field that
+// starts with a quote is still read up to its closing quote.
+func LoadTSV(name string, r io.Reader) ([]Problem, error) {
+
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = '\t'
+	csvReader.LazyQuotes = true
+
+	return loadDelimited(name, csvReader)
+}
+
+// loadDelimited reads every row from the CSV reader and then
+// converts them into Problems. Malformed rows are skipped and
+// reported along with any invalid problems, in order of line,
+// alongside the Problems that could be parsed.
+func loadDelimited(name string, csvReader *csv.Reader) ([]Problem, error) {
+
+	// Rows are validated by parseTable rather than the reader.
+	csvReader.FieldsPerRecord = -1
+
+	var rows []tableRow
+	var errs ParseErrors
+	for {
+		cells, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				errs = append(errs, &ParseError{name, csvErr.Line, csvErr.Err})
+				continue
+			}
+			return nil, err
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		rows = append(rows, tableRow{line, cells})
+	}
+
+	problems, err := parseTable(name, rows, false)
+	if len(errs) == 0 {
+		return problems, err
+	}
+
+	var tableErrs ParseErrors
+	if errors.As(err, &tableErrs) {
+		errs = append(errs, tableErrs...)
+	} else if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+
+	return problems, errs
+}
+
+// WriteCSV writes the question and answer of each Problem as a
+// row of CSV that LoadCSV reads back. If any Problem has a
+// Category, Tags or Difficulty, those columns are written too,
+// after a header row.
+func WriteCSV(w io.Writer, problems []Problem) error {
+
+	var category, tags, difficulty bool
+	for _, p := range problems {
+		category = category || p.Category != ""
+		tags = tags || len(p.Tags) > 0
+		difficulty = difficulty || p.Difficulty != 0
+	}
+
+	header := []string{"question", "answer"}
+	if category {
+		header = append(header, "category")
+	}
+	if tags {
+		header = append(header, "tags")
+	}
+	if difficulty {
+		header = append(header, "difficulty")
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if len(header) > 2 {
+		csvWriter.Write(header)
+	}
+	for _, p := range problems {
+		row := []string{p.Question, p.Answer}
+		if category {
+			row = append(row, p.Category)
+		}
+		if tags {
+			row = append(row, strings.Join(p.Tags, ";"))
+		}
+		if difficulty {
+			row = append(row, strconv.Itoa(p.Difficulty))
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
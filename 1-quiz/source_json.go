@@ -0,0 +1,83 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+func init() {
+	RegisterFormat("json", LoadJSON, ".json")
+}
+
+// LoadJSON is the Loader for problem sources written as a JSON
+// array of objects, e.g.
+//
+//	[
+//		{"question": "5+5", "answer": "10"},
+//		...
+//	]
+func LoadJSON(name string, r io.Reader) ([]Problem, error) {
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, ParseErrors{{name, 1, errors.New("expected a JSON array of problems")}}
+	}
+
+	var errs ParseErrors
+	var problems []Problem
+	for dec.More() {
+
+		line := lineAt(data, dec.InputOffset())
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line = lineAt(data, syntaxErr.Offset)
+			}
+			return nil, append(errs, &ParseError{name, line, err})
+		}
+
+		var rec record
+		recDec := json.NewDecoder(bytes.NewReader(raw))
+		recDec.DisallowUnknownFields()
+		if err := recDec.Decode(&rec); err != nil {
+			errs = append(errs, &ParseError{name, line, fmt.Errorf("invalid problem: %v", err)})
+			continue
+		}
+
+		p, err := rec.problem()
+		if err != nil {
+			errs = append(errs, &ParseError{name, line, err})
+			continue
+		}
+		p.Line = line
+		problems = append(problems, p)
+	}
+
+	return problems, errs.errorOrNil()
+}
+
+// lineAt returns the line of the first token at or after the
+// specified offset into data.
+func lineAt(data []byte, offset int64) int {
+
+	for offset < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,"), data[offset]) >= 0 {
+		offset++
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
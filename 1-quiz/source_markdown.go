@@ -0,0 +1,80 @@
+package quiz
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterFormat("markdown", LoadMarkdown, ".md", ".markdown")
+}
+
+// delimiterRow matches the row separating the header of a
+// Markdown table from its body, e.g. "|---|:---:|".
+var delimiterRow = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)*\s*:?-+:?\s*\|?$`)
+
+// LoadMarkdown is the Loader for problem sources written as a
+// Markdown table. Text outside of the table is ignored.
+//
+// The table must have a header row naming its columns, e.g.
+//
+//	| question | answer |
+//	|----------|--------|
+//	| 5+5      | 10     |
+//
+// A pipe within a cell is written as `\|`.
+func LoadMarkdown(name string, r io.Reader) ([]Problem, error) {
+
+	var rows []tableRow
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "|") {
+			// Only the first table in the source is read.
+			if len(rows) > 0 {
+				break
+			}
+			continue
+		}
+		if delimiterRow.MatchString(text) {
+			continue
+		}
+
+		rows = append(rows, tableRow{line, splitMarkdownRow(text)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return parseTable(name, rows, true)
+}
+
+// splitMarkdownRow returns the cells of a Markdown table row.
+func splitMarkdownRow(text string) []string {
+
+	text = strings.TrimPrefix(text, "|")
+	if strings.HasSuffix(text, "|") && !strings.HasSuffix(text, `\|`) {
+		text = strings.TrimSuffix(text, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && text[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case text[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(text[i])
+		}
+	}
+
+	return append(cells, cell.String())
+}
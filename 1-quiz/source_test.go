@@ -0,0 +1,172 @@
+package quiz
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		format string
+		source string
+
+		// questions are the lines of the problems that are
+		// parsed, and errors the lines of those that are not.
+		questions []int
+		errors    []int
+	}{
+		{
+			name:      "csv",
+			format:    "csv",
+			source:    "5+5,10\n\"what 2+2, sir?\",4\n8+3,11\n",
+			questions: []int{1, 2, 3},
+		},
+		{
+			name:      "csv with malformed rows",
+			format:    "csv",
+			source:    "5+5,10\n1+1\na \"quoted\" question,2\n8+3,11\n,4\n7+2,9\n",
+			questions: []int{1, 4, 6},
+			errors:    []int{2, 3, 5},
+		},
+		{
+			name:      "csv with a header",
+			format:    "csv",
+			source:    "question,answer,category\n5+5,10,arithmetic\n1+1,,arithmetic\n",
+			questions: []int{2},
+			errors:    []int{3},
+		},
+		{
+			name:      "tsv with quotes",
+			format:    "tsv",
+			source:    "5+5\t10\nsay \"hi\"\thi\n1+1\n8+3\t11\t30\tmany\n",
+			questions: []int{1, 2},
+			errors:    []int{3, 4},
+		},
+		{
+			name:   "json",
+			format: "json",
+			source: `[
+	{"question": "5+5", "answer": 10},
+	{"question": "1+1"},
+	{"question": "2+2", "answer": "4", "colour": "red"},
+	{"question": "8+3", "answer": "11", "points": "many"},
+	{"question": "7+2", "answer": "9"}
+]`,
+			questions: []int{2, 6},
+			errors:    []int{3, 4, 5},
+		},
+		{
+			name:   "json that is not an array",
+			format: "json",
+			source: `{"question": "5+5", "answer": 10}`,
+			errors: []int{1},
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			source: "- question: 5+5\n  answer: 10\n" +
+				"- question: 1+1\n" +
+				"- question: 2+2\n  answer: 4\n  colour: red\n" +
+				"- 8+3\n" +
+				"- question: 7+2\n  answer: 9\n",
+			questions: []int{1, 8},
+			errors:    []int{3, 4, 7},
+		},
+		{
+			name:   "yaml that is not a sequence",
+			format: "yaml",
+			source: "question: 5+5\nanswer: 10\n",
+			errors: []int{1},
+		},
+		{
+			name:   "markdown",
+			format: "markdown",
+			source: "Arithmetic\n\n" +
+				"| question | answer |\n" +
+				"|----------|:------:|\n" +
+				"| 5+5      | 10     |\n" +
+				"| 1+1      |\n" +
+				"| a \\| b  | c      |\n" +
+				"|          | 4      |\n" +
+				"\n| 8+3 | 11 |\n",
+			questions: []int{5, 7},
+			errors:    []int{6, 8},
+		},
+		{
+			name:   "markdown with an unknown column",
+			format: "markdown",
+			source: "| question | colour |\n|---|---|\n| 5+5 | red |\n",
+			errors: []int{1},
+		},
+	}
+
+	for _, test := range tests {
+
+		problems, err := Load(test.name, strings.NewReader(test.source), test.format)
+
+		var errs ParseErrors
+		if err != nil && !errors.As(err, &errs) {
+			t.Errorf("%s: got error %v, want ParseErrors", test.name, err)
+			continue
+		}
+		var errorLines []int
+		for _, e := range errs {
+			if e.File != test.name {
+				t.Errorf("%s: got an error in %q", test.name, e.File)
+			}
+			errorLines = append(errorLines, e.Line)
+		}
+		if !equalInts(errorLines, test.errors) {
+			t.Errorf("%s: got errors on lines %v, want %v:\n%v", test.name, errorLines, test.errors, err)
+		}
+
+		var questionLines []int
+		for _, p := range problems {
+			questionLines = append(questionLines, p.Line)
+		}
+		if !equalInts(questionLines, test.questions) {
+			t.Errorf("%s: got problems on lines %v, want %v", test.name, questionLines, test.questions)
+		}
+	}
+}
+
+func TestLoadMarkdownPipe(t *testing.T) {
+
+	problems, err := LoadMarkdown("", strings.NewReader("| question | answer |\n|--|--|\n| a \\| b | c |\n"))
+	if err != nil {
+		t.Fatalf("LoadMarkdown: %v", err)
+	}
+
+	if len(problems) != 1 || problems[0].Question != "a | b" || problems[0].Answer != "c" {
+		t.Errorf("got %+v, want the question \"a | b\"", problems)
+	}
+}
+
+func TestLintFileMalformedCSV(t *testing.T) {
+
+	// A malformed row does not stop the other rows from being
+	// checked.
+	path := filepath.Join(t.TempDir(), "problems.csv")
+	source := "5+5,10\na \"quoted\" question,2\n5+5,10\n1+1,2\n1+1,3\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics, err := LintFile(path, "")
+	if err != nil {
+		t.Fatalf("LintFile: %v", err)
+	}
+
+	var lines []int
+	for _, d := range diagnostics {
+		lines = append(lines, d.Line)
+	}
+	if want := []int{2, 3, 5}; !equalInts(lines, want) {
+		t.Errorf("got diagnostics on lines %v, want %v: %v", lines, want, diagnostics)
+	}
+}
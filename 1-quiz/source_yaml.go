@@ -0,0 +1,89 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	RegisterFormat("yaml", LoadYAML, ".yaml", ".yml")
+}
+
+// LoadYAML is the Loader for problem sources written as a YAML
+// sequence of mappings, e.g.
+//
+//   - question: 5+5
+//     answer: 10
+func LoadYAML(name string, r io.Reader) ([]Problem, error) {
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, ParseErrors{{name, 0, err}}
+	}
+
+	seq := &doc
+	if seq.Kind == yaml.DocumentNode && len(seq.Content) > 0 {
+		seq = seq.Content[0]
+	}
+	if seq.Kind != yaml.SequenceNode {
+		return nil, ParseErrors{{name, seq.Line, errors.New("expected a YAML sequence of problems")}}
+	}
+
+	var errs ParseErrors
+	var problems []Problem
+	for _, node := range seq.Content {
+
+		if err := checkYAMLFields(node); err != nil {
+			errs = append(errs, &ParseError{name, node.Line, err})
+			continue
+		}
+
+		var rec record
+		if err := node.Decode(&rec); err != nil {
+			errs = append(errs, &ParseError{name, node.Line, fmt.Errorf("invalid problem: %v", err)})
+			continue
+		}
+
+		p, err := rec.problem()
+		if err != nil {
+			errs = append(errs, &ParseError{name, node.Line, err})
+			continue
+		}
+		p.Line = node.Line
+		problems = append(problems, p)
+	}
+
+	return problems, errs.errorOrNil()
+}
+
+// checkYAMLFields returns an error if the node is not a
+// mapping of the fields of a record.
+func checkYAMLFields(node *yaml.Node) error {
+
+	if node.Kind != yaml.MappingNode {
+		return errors.New("expected a mapping of problem fields")
+	}
+
+	known := map[string]bool{}
+	t := reflect.TypeOf(record{})
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !known[key.Value] {
+			return fmt.Errorf("unknown field %q", key.Value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package quiz
+
+import (
+	"sort"
+	"time"
+)
+
+// QuestionStats summarizes every recorded answer to a question.
+type QuestionStats struct {
+	Question     string
+	Asked        int
+	Correct      int
+	TotalLatency time.Duration
+}
+
+// Accuracy returns the fraction of answers that were correct.
+func (qs QuestionStats) Accuracy() float64 {
+
+	if qs.Asked == 0 {
+		return 0
+	}
+
+	return float64(qs.Correct) / float64(qs.Asked)
+}
+
+// AverageLatency returns the mean time taken to answer.
+func (qs QuestionStats) AverageLatency() time.Duration {
+
+	if qs.Asked == 0 {
+		return 0
+	}
+
+	return qs.TotalLatency / time.Duration(qs.Asked)
+}
+
+// Stats summarizes a user's Attempts.
+type Stats struct {
+	Attempts []Attempt
+
+	// Questions holds the statistics for each question that was
+	// answered, ordered from the weakest to the strongest.
+	Questions []QuestionStats
+
+	// AverageLatency is the mean time taken to answer any
+	// question.
+	AverageLatency time.Duration
+}
+
+// ComputeStats summarizes the specified Attempts, which are
+// expected in the order they were made.
+func ComputeStats(attempts []Attempt) Stats {
+
+	stats := Stats{Attempts: attempts}
+
+	byQuestion := map[string]*QuestionStats{}
+	var answered int
+	var totalLatency time.Duration
+	for _, attempt := range attempts {
+		for _, a := range attempt.Answers {
+
+			qs, ok := byQuestion[a.Question]
+			if !ok {
+				qs = &QuestionStats{Question: a.Question}
+				byQuestion[a.Question] = qs
+			}
+			qs.Asked++
+			if a.Correct {
+				qs.Correct++
+			}
+			qs.TotalLatency += a.Latency
+
+			answered++
+			totalLatency += a.Latency
+		}
+	}
+	if answered > 0 {
+		stats.AverageLatency = totalLatency / time.Duration(answered)
+	}
+
+	for _, qs := range byQuestion {
+		stats.Questions = append(stats.Questions, *qs)
+	}
+	sort.Slice(stats.Questions, func(i, j int) bool {
+		a, b := stats.Questions[i], stats.Questions[j]
+		if a.Accuracy() != b.Accuracy() {
+			return a.Accuracy() < b.Accuracy()
+		}
+		if a.AverageLatency() != b.AverageLatency() {
+			return a.AverageLatency() > b.AverageLatency()
+		}
+		return a.Question < b.Question
+	})
+
+	return stats
+}
+
+// Percent returns the Attempt's score as a percentage of the
+// maximum score.
+func (a Attempt) Percent() float64 {
+
+	if a.MaxScore == 0 {
+		return 0
+	}
+
+	return 100 * a.Score / a.MaxScore
+}
+
+// Trend returns the change in mean percentage score between the
+// earlier and later halves of the Attempts. It is zero if there
+// are fewer than two Attempts.
+func (s Stats) Trend() float64 {
+
+	if len(s.Attempts) < 2 {
+		return 0
+	}
+
+	half := len(s.Attempts) / 2
+	mean := func(attempts []Attempt) float64 {
+		var total float64
+		for _, a := range attempts {
+			total += a.Percent()
+		}
+		return total / float64(len(attempts))
+	}
+
+	return mean(s.Attempts[len(s.Attempts)-half:]) - mean(s.Attempts[:half])
+}
@@ -0,0 +1,499 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrBracketDrawn is returned when changing the Teams of a
+// Tournament whose Bracket has already been drawn.
+var ErrBracketDrawn = errors.New("the bracket has already been drawn")
+
+// Tournament is a competition between Teams of players over a
+// number of Rounds. Once its Bracket is drawn, each Round also
+// decides a stage of single-elimination matches between Teams.
+//
+// A Tournament is kept between Rounds as JSON. See
+// LoadTournament.
+type Tournament struct {
+	Name    string  `json:"name"`
+	Teams   []Team  `json:"teams"`
+	Rounds  []Round `json:"rounds,omitempty"`
+	Bracket []Match `json:"bracket,omitempty"`
+}
+
+// Team is a named group of players.
+type Team struct {
+	Name    string   `json:"name"`
+	Players []string `json:"players"`
+}
+
+// Round is a quiz that every player still in the Tournament took
+// on the same Problems, drawn from the Sources with the Seed.
+type Round struct {
+	Number  int           `json:"number"`
+	Sources []string      `json:"sources"`
+	Seed    int64         `json:"seed"`
+	Played  time.Time     `json:"played"`
+	Scores  []PlayerScore `json:"scores"`
+}
+
+// PlayerScore is a player's Result in a Round.
+type PlayerScore struct {
+	Player   string        `json:"player"`
+	Team     string        `json:"team"`
+	Score    float64       `json:"score"`
+	MaxScore float64       `json:"max_score"`
+	Correct  int           `json:"correct"`
+	Total    int           `json:"total"`
+	Latency  time.Duration `json:"latency"`
+}
+
+// NewPlayerScore returns the score of a player's Result.
+func NewPlayerScore(player string, team string, result Result) PlayerScore {
+
+	score := PlayerScore{
+		Player:   player,
+		Team:     team,
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Correct:  result.Correct,
+		Total:    result.Total,
+	}
+	for _, a := range result.Answers {
+		score.Latency += a.Latency
+	}
+
+	return score
+}
+
+// Match is a game between two Teams at a Stage of the Bracket,
+// starting from 1 for the first. The Team without an opponent
+// in a Match with an empty Away is given a bye.
+type Match struct {
+	Stage  int    `json:"stage"`
+	Home   string `json:"home"`
+	Away   string `json:"away,omitempty"`
+	Winner string `json:"winner,omitempty"`
+
+	// Round is the number of the Round that decided the Match.
+	Round int `json:"round,omitempty"`
+}
+
+// NewTournament returns an empty Tournament.
+func NewTournament(name string) *Tournament {
+	return &Tournament{Name: name}
+}
+
+// LoadTournament reads a Tournament saved by Save.
+func LoadTournament(path string) (*Tournament, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Tournament
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return &t, nil
+}
+
+// Save writes the Tournament to the specified path as JSON,
+// replacing the file only once it is written in full.
+func (t *Tournament) Save(path string) error {
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(path, bytes.NewReader(append(data, '\n')))
+}
+
+// Register adds players to the named Team, which is created if
+// it does not exist. A player may only be on one Team.
+func (t *Tournament) Register(team string, players ...string) error {
+
+	team = strings.TrimSpace(team)
+	if team == "" {
+		return errors.New("missing team name")
+	}
+	if len(t.Bracket) > 0 {
+		return ErrBracketDrawn
+	}
+
+	for _, player := range players {
+		if other, ok := t.TeamOf(player); ok && other != team {
+			return fmt.Errorf("player %q is already on team %q", player, other)
+		}
+	}
+
+	i := t.teamIndex(team)
+	if i < 0 {
+		t.Teams = append(t.Teams, Team{Name: team})
+		i = len(t.Teams) - 1
+	}
+	for _, player := range players {
+		player = strings.TrimSpace(player)
+		if _, ok := t.TeamOf(player); player != "" && !ok {
+			t.Teams[i].Players = append(t.Teams[i].Players, player)
+		}
+	}
+
+	return nil
+}
+
+// TeamOf returns the name of the player's Team.
+func (t *Tournament) TeamOf(player string) (string, bool) {
+
+	for _, team := range t.Teams {
+		for _, p := range team.Players {
+			if p == player {
+				return team.Name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// teamIndex returns the index of the named Team, or -1.
+func (t *Tournament) teamIndex(name string) int {
+
+	for i, team := range t.Teams {
+		if team.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Playing returns the Teams that take part in the next Round:
+// every Team until the Bracket is drawn, and after that the
+// Teams of the undecided Matches.
+func (t *Tournament) Playing() []Team {
+
+	if len(t.Bracket) == 0 {
+		return append([]Team(nil), t.Teams...)
+	}
+
+	var teams []Team
+	for _, m := range t.Bracket {
+		if m.Winner != "" {
+			continue
+		}
+		for _, name := range []string{m.Home, m.Away} {
+			if i := t.teamIndex(name); i >= 0 {
+				teams = append(teams, t.Teams[i])
+			}
+		}
+	}
+
+	return teams
+}
+
+// Champion returns the winner of the final of the Bracket, once
+// it has been decided.
+func (t *Tournament) Champion() (string, bool) {
+
+	if len(t.Bracket) == 0 {
+		return "", false
+	}
+	final := t.Bracket[len(t.Bracket)-1]
+	if final.Winner == "" || t.stageSize(final.Stage) != 1 {
+		return "", false
+	}
+
+	return final.Winner, true
+}
+
+// Eliminated reports whether the named Team has lost a Match.
+func (t *Tournament) Eliminated(team string) bool {
+
+	for _, m := range t.Bracket {
+		if m.Winner != "" && m.Winner != team && (m.Home == team || m.Away == team) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stageSize returns the number of Matches at a Stage.
+func (t *Tournament) stageSize(stage int) int {
+
+	n := 0
+	for _, m := range t.Bracket {
+		if m.Stage == stage {
+			n++
+		}
+	}
+
+	return n
+}
+
+// DrawBracket seeds the Teams into a single-elimination Bracket
+// by their Standings, so that the strongest Teams meet as late
+// as possible. Teams left without an opponent, when the number
+// of Teams is not a power of two, are given byes.
+func (t *Tournament) DrawBracket() error {
+
+	if len(t.Bracket) > 0 {
+		return ErrBracketDrawn
+	}
+	if len(t.Teams) < 2 {
+		return fmt.Errorf("expected at least 2 teams but got %d", len(t.Teams))
+	}
+
+	standings := t.Standings()
+	size := 1
+	for size < len(standings) {
+		size *= 2
+	}
+
+	order := bracketOrder(size)
+	for i := 0; i < size; i += 2 {
+		home, away := order[i], order[i+1]
+		m := Match{Stage: 1, Home: standings[home-1].Team}
+		if away <= len(standings) {
+			m.Away = standings[away-1].Team
+		} else {
+			m.Winner = m.Home
+		}
+		t.Bracket = append(t.Bracket, m)
+	}
+	t.advance()
+
+	return nil
+}
+
+// bracketOrder returns the seeds, from 1, in the order they are
+// placed in a Bracket of the specified size, e.g. 1, 4, 2, 3.
+// Each seed is paired with the next, and adjacent pairs meet at
+// the next Stage.
+func bracketOrder(size int) []int {
+
+	order := []int{1}
+	for len(order) < size {
+		n := 2 * len(order)
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+
+	return order
+}
+
+// AddRound records a Round, numbering it after those before. If
+// the Bracket has been drawn, the Round decides its undecided
+// Matches: a Team without scores in the Round loses, and
+// otherwise the Team with the higher Percent wins, then the one
+// with more correct answers, then the one whose players were
+// faster on average, and otherwise the higher seed.
+func (t *Tournament) AddRound(round Round) Round {
+
+	round.Number = len(t.Rounds) + 1
+	t.Rounds = append(t.Rounds, round)
+
+	for i := range t.Bracket {
+		m := &t.Bracket[i]
+		if m.Winner != "" {
+			continue
+		}
+		m.Winner, m.Round = m.Home, round.Number
+		if roundBeats(round, m.Away, m.Home) {
+			m.Winner = m.Away
+		}
+	}
+	t.advance()
+
+	return round
+}
+
+// roundBeats reports whether team a did better than team b in
+// the Round.
+func roundBeats(round Round, a string, b string) bool {
+
+	sa, na := round.teamScore(a)
+	sb, nb := round.teamScore(b)
+	if na == 0 || nb == 0 {
+		return na > 0
+	}
+	if sa.Percent() != sb.Percent() {
+		return sa.Percent() > sb.Percent()
+	}
+	if sa.Correct != sb.Correct {
+		return sa.Correct > sb.Correct
+	}
+
+	// Teams may have different numbers of players, so their
+	// speed is compared per player.
+	return sa.Latency/time.Duration(na) < sb.Latency/time.Duration(nb)
+}
+
+// teamScore adds up the scores of a Team's players in the Round,
+// and returns how many of them have a score.
+func (r Round) teamScore(team string) (PlayerScore, int) {
+
+	total, n := PlayerScore{Team: team}, 0
+	for _, s := range r.Scores {
+		if s.Team != team {
+			continue
+		}
+		total.Score += s.Score
+		total.MaxScore += s.MaxScore
+		total.Correct += s.Correct
+		total.Total += s.Total
+		total.Latency += s.Latency
+		n++
+	}
+
+	return total, n
+}
+
+// Percent returns the score as a percentage of the maximum
+// score.
+func (s PlayerScore) Percent() float64 {
+
+	if s.MaxScore == 0 {
+		return 0
+	}
+
+	return 100 * s.Score / s.MaxScore
+}
+
+// advance draws the next Stage of the Bracket once every Match
+// of the last Stage is decided, pairing the winners of adjacent
+// Matches.
+func (t *Tournament) advance() {
+
+	for len(t.Bracket) > 0 {
+		last := t.Bracket[len(t.Bracket)-1].Stage
+		var winners []string
+		for _, m := range t.Bracket {
+			if m.Stage != last {
+				continue
+			}
+			if m.Winner == "" {
+				return
+			}
+			winners = append(winners, m.Winner)
+		}
+		if len(winners) < 2 {
+			return
+		}
+
+		for i := 0; i < len(winners); i += 2 {
+			t.Bracket = append(t.Bracket, Match{Stage: last + 1, Home: winners[i], Away: winners[i+1]})
+		}
+	}
+}
+
+// TeamStanding aggregates a Team's scores over every Round.
+type TeamStanding struct {
+	Team    string
+	Players int
+
+	// Rounds holds the Team's percentage in each Round, or -1 if
+	// it did not play.
+	Rounds []float64
+
+	Score    float64
+	MaxScore float64
+	Correct  int
+	Total    int
+
+	// Best is the player with the highest total Score.
+	Best string
+
+	// Reached is the last Stage of the Bracket the Team played at,
+	// or 0 before the Bracket is drawn.
+	Reached    int
+	Eliminated bool
+}
+
+// Percent returns the Team's score as a percentage of the
+// maximum score.
+func (s TeamStanding) Percent() float64 {
+
+	if s.MaxScore == 0 {
+		return 0
+	}
+
+	return 100 * s.Score / s.MaxScore
+}
+
+// Standings returns the aggregate of each Team, ranked by
+// Percent and then Score. Teams still in the Bracket rank above
+// those eliminated, who rank by the Stage they Reached.
+func (t *Tournament) Standings() []TeamStanding {
+
+	var standings []TeamStanding
+	for _, team := range t.Teams {
+
+		s := TeamStanding{
+			Team:       team.Name,
+			Players:    len(team.Players),
+			Eliminated: t.Eliminated(team.Name),
+		}
+		for _, m := range t.Bracket {
+			if m.Home == team.Name || m.Away == team.Name {
+				s.Reached = m.Stage
+			}
+		}
+		players := map[string]float64{}
+		for _, round := range t.Rounds {
+			total, _ := round.teamScore(team.Name)
+			percent := -1.0
+			if total.MaxScore > 0 {
+				percent = total.Percent()
+			}
+			s.Rounds = append(s.Rounds, percent)
+
+			s.Score += total.Score
+			s.MaxScore += total.MaxScore
+			s.Correct += total.Correct
+			s.Total += total.Total
+			for _, ps := range round.Scores {
+				if ps.Team == team.Name {
+					players[ps.Player] += ps.Score
+				}
+			}
+		}
+		for _, player := range team.Players {
+			if score, ok := players[player]; ok && (s.Best == "" || score > players[s.Best]) {
+				s.Best = player
+			}
+		}
+
+		standings = append(standings, s)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if a.Eliminated && a.Reached != b.Reached {
+			return a.Reached > b.Reached
+		}
+		if a.Percent() != b.Percent() {
+			return a.Percent() > b.Percent()
+		}
+		return a.Score > b.Score
+	})
+
+	return standings
+}
@@ -0,0 +1,178 @@
+package quiz
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+// WriteTournamentCSV writes a row of CSV for each Team of the
+// Tournament in order of its Standings, after a header row, with
+// the Team's percentage in each Round. A Round the Team did not
+// play is left empty.
+func WriteTournamentCSV(w io.Writer, t *Tournament) error {
+
+	header := []string{"rank", "team", "players", "best_player"}
+	for _, round := range t.Rounds {
+		header = append(header, fmt.Sprintf("round_%d", round.Number))
+	}
+	header = append(header, "score", "max_score", "correct", "total", "percent", "status")
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write(header)
+	champion, _ := t.Champion()
+	for i, s := range t.Standings() {
+		row := []string{strconv.Itoa(i + 1), s.Team, strconv.Itoa(s.Players), s.Best}
+		for _, percent := range s.Rounds {
+			row = append(row, formatPercent(percent))
+		}
+		row = append(row,
+			strconv.FormatFloat(s.Score, 'g', -1, 64),
+			strconv.FormatFloat(s.MaxScore, 'g', -1, 64),
+			strconv.Itoa(s.Correct),
+			strconv.Itoa(s.Total),
+			formatPercent(s.Percent()),
+			teamStatus(t, s, champion),
+		)
+		csvWriter.Write(row)
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// formatPercent formats a percentage to one decimal place, or as
+// empty if it is negative.
+func formatPercent(percent float64) string {
+
+	if percent < 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(percent, 'f', 1, 64)
+}
+
+// teamStatus describes where a Team stands in the Bracket.
+func teamStatus(t *Tournament, s TeamStanding, champion string) string {
+
+	switch {
+	case s.Team == champion:
+		return "champion"
+	case s.Eliminated:
+		return "eliminated"
+	case len(t.Bracket) > 0:
+		return "playing"
+	default:
+		return "registered"
+	}
+}
+
+// tournamentPage is the data of the HTML report of a Tournament.
+type tournamentPage struct {
+	*Tournament
+	Standings []tournamentStanding
+	Stages    [][]Match
+	Champion  string
+}
+
+type tournamentStanding struct {
+	TeamStanding
+	Rank    int
+	Rounds  []string
+	Percent string
+	Status  string
+}
+
+// WriteTournamentHTML writes the Tournament as an HTML page with
+// its Standings, the Bracket by stage, and each player's score
+// in every Round.
+func WriteTournamentHTML(w io.Writer, t *Tournament) error {
+
+	page := tournamentPage{Tournament: t}
+	page.Champion, _ = t.Champion()
+	for i, s := range t.Standings() {
+		standing := tournamentStanding{
+			TeamStanding: s,
+			Rank:         i + 1,
+			Percent:      formatPercent(s.Percent()),
+			Status:       teamStatus(t, s, page.Champion),
+		}
+		for _, percent := range s.Rounds {
+			standing.Rounds = append(standing.Rounds, formatPercent(percent))
+		}
+		page.Standings = append(page.Standings, standing)
+	}
+	for _, m := range t.Bracket {
+		if m.Stage > len(page.Stages) {
+			page.Stages = append(page.Stages, nil)
+		}
+		page.Stages[m.Stage-1] = append(page.Stages[m.Stage-1], m)
+	}
+
+	return tournamentTemplate.Execute(w, page)
+}
+
+const TOURNAMENT_TEMPLATE = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>{{.Name}}</title>
+</head>
+<body>
+	<h1>{{.Name}}</h1>
+	{{if .Champion}}<p>Champion: <strong>{{.Champion}}</strong></p>{{end}}
+
+	<h2>Standings</h2>
+	<table>
+		<tr>
+			<th>Rank</th><th>Team</th><th>Players</th><th>Best player</th>
+			{{range .Rounds}}<th>Round {{.Number}}</th>{{end}}
+			<th>Score</th><th>Correct</th><th>Percent</th><th>Status</th>
+		</tr>
+		{{range .Standings}}
+		<tr>
+			<td>{{.Rank}}</td><td>{{.Team}}</td><td>{{.Players}}</td><td>{{.Best}}</td>
+			{{range .Rounds}}<td>{{.}}</td>{{end}}
+			<td>{{.Score}} / {{.MaxScore}}</td><td>{{.Correct}} / {{.Total}}</td>
+			<td>{{.Percent}}</td><td>{{.Status}}</td>
+		</tr>
+		{{end}}
+	</table>
+
+	{{if .Stages}}
+	<h2>Bracket</h2>
+	{{range $i, $stage := .Stages}}
+		<h3>Stage {{inc $i}}</h3>
+		<ul>
+		{{range $stage}}
+			<li>
+				{{if .Away}}{{.Home}} vs {{.Away}}{{else}}{{.Home}} (bye){{end}}
+				{{if .Winner}}&mdash; won by <strong>{{.Winner}}</strong>{{if .Round}} in round {{.Round}}{{end}}{{end}}
+			</li>
+		{{end}}
+		</ul>
+	{{end}}
+	{{end}}
+
+	{{range .Rounds}}
+	<h2>Round {{.Number}}</h2>
+	<p>Played {{.Played.Format "2006-01-02 15:04"}} on {{range $i, $s := .Sources}}{{if $i}}, {{end}}{{$s}}{{end}} (seed {{.Seed}}).</p>
+	<table>
+		<tr><th>Player</th><th>Team</th><th>Score</th><th>Correct</th><th>Time</th></tr>
+		{{range .Scores}}
+		<tr>
+			<td>{{.Player}}</td><td>{{.Team}}</td><td>{{.Score}} / {{.MaxScore}}</td>
+			<td>{{.Correct}} / {{.Total}}</td><td>{{.Latency.Round 1000000}}</td>
+		</tr>
+		{{end}}
+	</table>
+	{{end}}
+</body>
+</html>
+`
+
+var tournamentTemplate = template.Must(template.New("TournamentTemplate").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(TOURNAMENT_TEMPLATE))
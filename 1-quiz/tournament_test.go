@@ -0,0 +1,249 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newTestTournament returns a Tournament of Teams named "A", "B"
+// and so on, seeded in that order, each of a single player.
+func newTestTournament(t *testing.T, teams int) *Tournament {
+
+	t.Helper()
+	tournament := NewTournament("test")
+	for i := 0; i < teams; i++ {
+		name := string(rune('A' + i))
+		if err := tournament.Register(name, "player "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return tournament
+}
+
+// matches formats the Matches of the Bracket at a Stage, e.g.
+// "A-D B-C", with a winner marked by a star and a bye written
+// as the Team alone.
+func matches(tournament *Tournament, stage int) string {
+
+	s := ""
+	for _, m := range tournament.Bracket {
+		if m.Stage != stage {
+			continue
+		}
+		if s != "" {
+			s += " "
+		}
+		for i, team := range []string{m.Home, m.Away} {
+			if team == "" {
+				continue
+			}
+			if i > 0 {
+				s += "-"
+			}
+			s += team
+			if m.Winner == team && m.Away != "" {
+				s += "*"
+			}
+		}
+	}
+
+	return s
+}
+
+// wins returns a Round in which the named Teams answer every
+// question correctly and the others none.
+func wins(tournament *Tournament, winners ...string) Round {
+
+	var round Round
+	for _, team := range tournament.Playing() {
+		score := PlayerScore{Player: team.Players[0], Team: team.Name, MaxScore: 1, Total: 1}
+		for _, winner := range winners {
+			if winner == team.Name {
+				score.Score, score.Correct = 1, 1
+			}
+		}
+		round.Scores = append(round.Scores, score)
+	}
+
+	return round
+}
+
+func TestBracketOrder(t *testing.T) {
+
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{1, []int{1}},
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{16, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}},
+	}
+
+	for _, test := range tests {
+		if got := bracketOrder(test.size); !equalInts(got, test.want) {
+			t.Errorf("bracketOrder(%d): got %v, want %v", test.size, got, test.want)
+		}
+	}
+}
+
+func TestDrawBracket(t *testing.T) {
+
+	// Byes go to the highest seeds.
+	tests := []struct {
+		teams int
+		want  string
+	}{
+		{2, "A-B"},
+		{3, "A B-C"},
+		{4, "A-D B-C"},
+		{5, "A D-E B C"},
+		{6, "A D-E B C-F"},
+		{7, "A D-E B-G C-F"},
+		{8, "A-H D-E B-G C-F"},
+	}
+
+	for _, test := range tests {
+		tournament := newTestTournament(t, test.teams)
+		if err := tournament.DrawBracket(); err != nil {
+			t.Fatalf("%d teams: %v", test.teams, err)
+		}
+		if got := matches(tournament, 1); got != test.want {
+			t.Errorf("%d teams: got the first stage %q, want %q", test.teams, got, test.want)
+		}
+		if got := matches(tournament, 2); got != "" {
+			t.Errorf("%d teams: got the second stage %q before the first was played", test.teams, got)
+		}
+	}
+
+	// Teams are seeded by their Standings.
+	tournament := newTestTournament(t, 4)
+	tournament.AddRound(wins(tournament, "C", "D"))
+	tournament.DrawBracket()
+	if got := matches(tournament, 1); got != "C-B D-A" {
+		t.Errorf("got the first stage %q, want the winners of the round seeded first", got)
+	}
+}
+
+func TestDrawBracketErrors(t *testing.T) {
+
+	if err := newTestTournament(t, 1).DrawBracket(); err == nil {
+		t.Error("DrawBracket of one team: got no error")
+	}
+
+	tournament := newTestTournament(t, 2)
+	tournament.DrawBracket()
+	if err := tournament.DrawBracket(); !errors.Is(err, ErrBracketDrawn) {
+		t.Errorf("DrawBracket once drawn: got error %v, want %v", err, ErrBracketDrawn)
+	}
+	if err := tournament.Register("C", "player C"); !errors.Is(err, ErrBracketDrawn) {
+		t.Errorf("Register once drawn: got error %v, want %v", err, ErrBracketDrawn)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+
+	tournament := newTestTournament(t, 5)
+	tournament.DrawBracket()
+
+	// Each Round decides the undecided Matches, and the winners
+	// of adjacent Matches meet at the next Stage.
+	rounds := []struct {
+		winners []string
+		stages  []string
+	}{
+		{[]string{"E"}, []string{"A D-E* B C", "A-E B-C"}},
+		{[]string{"E", "C"}, []string{"A D-E* B C", "A-E* B-C*", "E-C"}},
+		{[]string{"C"}, []string{"A D-E* B C", "A-E* B-C*", "E-C*"}},
+	}
+
+	for i, round := range rounds {
+		if _, ok := tournament.Champion(); ok {
+			t.Fatalf("round %d: the champion was decided early", i+1)
+		}
+		tournament.AddRound(wins(tournament, round.winners...))
+
+		for stage, want := range round.stages {
+			if got := matches(tournament, stage+1); got != want {
+				t.Errorf("round %d: got the stage %d %q, want %q", i+1, stage+1, got, want)
+			}
+		}
+		if got := matches(tournament, len(round.stages)+1); got != "" {
+			t.Errorf("round %d: got the stage %d %q too early", i+1, len(round.stages)+1, got)
+		}
+	}
+
+	if champion, ok := tournament.Champion(); !ok || champion != "C" {
+		t.Errorf("got the champion %q, want C", champion)
+	}
+	for _, team := range []string{"A", "B", "D", "E"} {
+		if !tournament.Eliminated(team) {
+			t.Errorf("%s was not eliminated", team)
+		}
+	}
+	if playing := tournament.Playing(); len(playing) != 0 {
+		t.Errorf("got %d teams playing once the final was decided", len(playing))
+	}
+}
+
+func TestRoundBeats(t *testing.T) {
+
+	// score returns the score of a player of a Team who answered
+	// correct of 4 questions worth a point each in total seconds.
+	score := func(team string, correct int, seconds int) PlayerScore {
+		return PlayerScore{
+			Player:   fmt.Sprintf("%s%d", team, correct),
+			Team:     team,
+			Score:    float64(correct),
+			MaxScore: 4,
+			Correct:  correct,
+			Total:    4,
+			Latency:  time.Duration(seconds) * time.Second,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		scores []PlayerScore
+		want   bool
+	}{
+		{"higher percent", []PlayerScore{score("a", 3, 9), score("b", 2, 1)}, true},
+		{"lower percent", []PlayerScore{score("a", 2, 1), score("b", 3, 9)}, false},
+		{
+			"more correct at the same percent",
+			[]PlayerScore{score("a", 2, 9), score("a", 2, 9), score("b", 2, 1)},
+			true,
+		},
+		{"faster", []PlayerScore{score("a", 2, 5), score("b", 2, 6)}, true},
+		{"slower", []PlayerScore{score("a", 2, 6), score("b", 2, 5)}, false},
+		{"as fast", []PlayerScore{score("a", 2, 5), score("b", 2, 5)}, false},
+
+		// Latency is compared per player, not in total.
+		{
+			"faster per player",
+			[]PlayerScore{score("a", 1, 5), score("a", 1, 5), {Player: "b", Team: "b", Score: 2, MaxScore: 8, Correct: 2, Total: 8, Latency: 6 * time.Second}},
+			true,
+		},
+		{
+			"slower per player",
+			[]PlayerScore{{Player: "a", Team: "a", Score: 2, MaxScore: 8, Correct: 2, Total: 8, Latency: 6 * time.Second}, score("b", 1, 5), score("b", 1, 5)},
+			false,
+		},
+
+		// A Team without scores loses, however badly the other did.
+		{"no scores", []PlayerScore{score("b", 0, 60)}, false},
+		{"against no scores", []PlayerScore{score("a", 0, 60)}, true},
+		{"neither scored", nil, false},
+	}
+
+	for _, test := range tests {
+		round := Round{Scores: test.scores}
+		if got := roundBeats(round, "a", "b"); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/text/message"
+)
+
+// ANSI escape sequences used by the TerminalView.
+const (
+	clearScreen  = "\x1b[H\x1b[2J"
+	saveCursor   = "\x1b7"
+	loadCursor   = "\x1b8"
+	clearLine    = "\x1b[2K"
+	colorGreen   = "\x1b[32m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorDefault = "\x1b[0m"
+)
+
+// Rows of the TerminalView's status lines.
+const (
+	timerRow  = 2
+	statusRow = 3
+)
+
+// TerminalView is a full-screen View for an ANSI terminal. Each
+// question is shown on a screen of its own beneath the progress
+// through the quiz, the running score, a bar counting down the
+// time left and whether the last answer was right or wrong.
+//
+// The status lines are redrawn in place, leaving the cursor
+// where the user is typing their answer, so the terminal need
+// not be put into raw mode.
+type TerminalView struct {
+	out     io.Writer
+	printer *message.Printer
+	width   int
+
+	// status is the line describing the last answer, or that
+	// time is up or the quiz is paused.
+	status string
+}
+
+// NewTerminalView returns a TerminalView that draws to a
+// terminal of the specified width, in the printer's language.
+func NewTerminalView(out io.Writer, printer *message.Printer, width int) *TerminalView {
+	return &TerminalView{out: out, printer: printer, width: width}
+}
+
+func (v *TerminalView) Ask(s *Session, prompt Prompt) {
+
+	problem := prompt.Problem
+
+	io.WriteString(v.out, clearScreen)
+	v.drawProgress(s)
+	io.WriteString(v.out, "\n")
+	v.drawTimer(s)
+	io.WriteString(v.out, "\n")
+	v.drawStatus()
+
+	question := fmt.Sprintf("\n\n%s?", problem.Question)
+	if hint := prompt.Hint(); hint != "" {
+		question += fmt.Sprintf(" (%s)", v.printer.Sprintf(hint))
+	}
+	fmt.Fprintln(v.out, question)
+	writeMedia(v.out, v.printer, problem, true)
+	for j, option := range prompt.Options {
+		fmt.Fprintf(v.out, "   %s) %s\n", Letter(j), optionText(v.printer, prompt, option))
+	}
+	io.WriteString(v.out, "\n> ")
+}
+
+func (v *TerminalView) Countdown(s *Session, seconds int) {
+	v.Refresh(s)
+}
+
+func (v *TerminalView) Answered(s *Session, answer Answer) {
+
+	switch {
+	case answer.Correct:
+		v.status = colorGreen + "✓ " + v.printer.Sprintf("Correct!") + colorDefault
+	case answer.TimedOut:
+		v.status = colorRed + "✗ " +
+			v.printer.Sprintf("Time's up! The answer was %q.", answer.Problem.Answer) + colorDefault
+	default:
+		v.status = colorRed + "✗ " +
+			v.printer.Sprintf("Incorrect. The answer was %q.", answer.Problem.Answer) + colorDefault
+	}
+	v.redraw(statusRow, v.drawStatus)
+}
+
+func (v *TerminalView) TimeUp(s *Session) {
+
+	v.status = colorRed + strings.TrimSpace(v.printer.Sprintf("Time's up!\n")) + colorDefault
+	v.redraw(statusRow, v.drawStatus)
+}
+
+func (v *TerminalView) Paused(s *Session) {
+
+	v.status = colorYellow + strings.TrimSpace(v.printer.Sprintf("Paused. Press enter to resume.\n")) + colorDefault
+	v.redraw(statusRow, v.drawStatus)
+	v.status = ""
+}
+
+// Refresh redraws the bar counting down the time left.
+func (v *TerminalView) Refresh(s *Session) {
+	v.redraw(timerRow, func() { v.drawTimer(s) })
+}
+
+// Close moves the cursor below the quiz so that whatever is
+// written next starts on a fresh line.
+func (v *TerminalView) Close() {
+	io.WriteString(v.out, "\n\n")
+}
+
+// redraw replaces the specified row, leaving the cursor where it
+// was.
+func (v *TerminalView) redraw(row int, draw func()) {
+
+	fmt.Fprintf(v.out, "%s\x1b[%d;1H%s", saveCursor, row, clearLine)
+	draw()
+	io.WriteString(v.out, loadCursor)
+}
+
+// drawProgress writes the question number and running score.
+func (v *TerminalView) drawProgress(s *Session) {
+
+	result := s.Result()
+	progress := v.printer.Sprintf("Question %d of %d", s.Number(), result.Total)
+	score := v.printer.Sprintf("Score: %g/%g", result.Score, result.MaxScore)
+
+	gap := v.width - len([]rune(progress)) - len([]rune(score))
+	if gap < 2 {
+		gap = 2
+	}
+	fmt.Fprintf(v.out, "%s%s%s", progress, strings.Repeat(" ", gap), score)
+}
+
+// drawTimer writes a bar of the time left on the question, or on
+// the quiz if the question has no TimeLimit of its own.
+func (v *TerminalView) drawTimer(s *Session) {
+
+	deadline, total := s.QuestionDeadline(), time.Duration(0)
+	if !deadline.IsZero() {
+		total = s.current.Problem.TimeLimit
+	} else {
+		deadline, total = s.Deadline(), s.quiz.TimeLimit
+	}
+	if deadline.IsZero() {
+		io.WriteString(v.out, v.printer.Sprintf("No time limit"))
+		return
+	}
+
+	now := s.clock.Now()
+	if s.Paused() {
+		now = s.paused
+	}
+	left := deadline.Sub(now)
+	if left < 0 {
+		left = 0
+	}
+	if total < left {
+		total = left
+	}
+
+	width := v.width - 12
+	if width > 60 {
+		width = 60
+	}
+	if width < 10 {
+		width = 10
+	}
+	filled := width
+	if total > 0 {
+		filled = int(float64(width) * float64(left) / float64(total))
+	}
+
+	color := colorGreen
+	if left <= countdownFrom {
+		color = colorRed
+	}
+	fmt.Fprintf(
+		v.out, "%s%s%s%s %s",
+		color, strings.Repeat("█", filled), strings.Repeat("░", width-filled), colorDefault,
+		v.printer.Sprintf("%ds", int((left+time.Second-1)/time.Second)),
+	)
+}
+
+// drawStatus writes the line describing the last answer.
+func (v *TerminalView) drawStatus() {
+	io.WriteString(v.out, v.status)
+}
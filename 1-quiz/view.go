@@ -0,0 +1,128 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/text/message"
+)
+
+// View presents a Session to the user as it is played. Every
+// method is given the Session so that the View can show its
+// progress, score and time remaining.
+type View interface {
+	// Ask shows a question as it is asked, and again when the
+	// quiz resumes from a pause.
+	Ask(s *Session, prompt Prompt)
+
+	// Countdown is called for each of the final seconds of a
+	// question's TimeLimit.
+	Countdown(s *Session, seconds int)
+
+	// Answered shows whether an answer, or a question that timed
+	// out, was correct.
+	Answered(s *Session, answer Answer)
+
+	// TimeUp is called when the question's or the quiz's
+	// TimeLimit is exceeded.
+	TimeUp(s *Session)
+
+	// Paused is called when the clock is stopped, until the user
+	// presses enter.
+	Paused(s *Session)
+}
+
+// Refresher is implemented by a View that redraws itself as time
+// passes, e.g. to animate a countdown.
+type Refresher interface {
+	Refresh(s *Session)
+}
+
+// REFRESH_INTERVAL is how often a Refresher is refreshed.
+const REFRESH_INTERVAL = 250 * time.Millisecond
+
+// lineView presents a Session as plain lines of text.
+type lineView struct {
+	out     io.Writer
+	printer *message.Printer
+	color   bool
+}
+
+// NewLineView returns a View that writes each question, and the
+// countdown to its TimeLimit, as plain lines of text in the
+// printer's language. Code snippets are highlighted in color if
+// color is set, e.g. when writing to a terminal.
+func NewLineView(out io.Writer, printer *message.Printer, color bool) View {
+	return &lineView{out, printer, color}
+}
+
+func (v *lineView) Ask(s *Session, prompt Prompt) {
+
+	problem := prompt.Problem
+
+	limitText := ""
+	if problem.TimeLimit > 0 {
+		limitText = fmt.Sprintf(" [%s]", problem.TimeLimit)
+	}
+	if hint := prompt.Hint(); hint != "" {
+		limitText = fmt.Sprintf(" (%s)%s", v.printer.Sprintf(hint), limitText)
+	}
+	fmt.Fprintf(v.out, "%d. %s?%s\n", s.Number(), problem.Question, limitText)
+	writeMedia(v.out, v.printer, problem, v.color)
+	for j, option := range prompt.Options {
+		fmt.Fprintf(v.out, "   %s) %s\n", Letter(j), optionText(v.printer, prompt, option))
+	}
+}
+
+func (v *lineView) Countdown(s *Session, seconds int) {
+	v.printer.Fprintf(v.out, "%d...\n", seconds)
+}
+
+func (v *lineView) Answered(s *Session, answer Answer) {}
+
+func (v *lineView) TimeUp(s *Session) {
+	v.printer.Fprintf(v.out, "Time's up!\n")
+}
+
+func (v *lineView) Paused(s *Session) {
+	v.printer.Fprintf(v.out, "Paused. Press enter to resume.\n")
+}
+
+// writeMedia writes the references to a Problem's image and
+// audio, followed by its code snippet, indented beneath the
+// question.
+func writeMedia(out io.Writer, printer *message.Printer, problem Problem, color bool) {
+
+	if problem.Image != "" {
+		fmt.Fprintf(out, "   [%s]\n", printer.Sprintf("Image: %s", problem.Image))
+	}
+	if problem.Audio != "" {
+		fmt.Fprintf(out, "   [%s]\n", printer.Sprintf("Audio: %s", problem.Audio))
+	}
+	if problem.Code == "" {
+		return
+	}
+
+	code := problem.Code
+	if color {
+		code = Highlight(code, problem.Language)
+	}
+	fmt.Fprintln(out)
+	for _, line := range strings.Split(code, "\n") {
+		fmt.Fprintf(out, "    %s\n", line)
+	}
+	fmt.Fprintln(out)
+}
+
+// optionText returns an option as it is displayed, translating
+// the options of true/false questions.
+func optionText(printer *message.Printer, prompt Prompt, option string) string {
+
+	if prompt.Problem.Type == TypeTrueFalse {
+		return printer.Sprintf(option)
+	}
+
+	return option
+}
@@ -0,0 +1,344 @@
+package urlshort
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LINKS_PREFIX is the path the LinksAPI is served under.
+const LINKS_PREFIX = "/api/links"
+
+// MAX_LINK_BODY is the largest request body the LinksAPI reads.
+const MAX_LINK_BODY = 1 << 16
+
+// LinkStore is a Store whose paths can be listed and changed,
+// such as a BoltStore.
+type LinkStore interface {
+	Lister
+	Editor
+}
+
+// LinksAPI is an http.Handler of a JSON API to list, create,
+// update and delete the links of a LinkStore:
+//
+//	GET    /api/links          list every link
+//	POST   /api/links          create a link: {"path": "...", "url": "..."}
+//	GET    /api/links/<path>   get the link of /<path>
+//	PUT    /api/links/<path>   create or update the link of /<path>: {"url": "..."}
+//	DELETE /api/links/<path>   delete the link of /<path>
+//
+// Every request must carry the Token as a bearer token in its
+// Authorization header. Errors are returned as JSON in the
+// format {"error": "..."}.
+type LinksAPI struct {
+	Store LinkStore
+
+	// Others are looked up for paths mapped elsewhere, which
+	// links may not take over.
+	Others Store
+
+	Token string
+
+	// Reserved are paths that links may not take, nor any path
+	// below them.
+	Reserved []string
+}
+
+// NewLinksAPI returns a LinksAPI of the links in the store,
+// which may not conflict with the paths in others (if not nil)
+// nor with LINKS_PREFIX.
+func NewLinksAPI(store LinkStore, others Store, token string) *LinksAPI {
+	return &LinksAPI{Store: store, Others: others, Token: token, Reserved: []string{"/api"}}
+}
+
+// apiError is an error with the HTTP status it is returned with.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// errorf returns an apiError with the status and a formatted
+// message.
+func errorf(status int, format string, a ...interface{}) error {
+	return &apiError{status, fmt.Sprintf(format, a...)}
+}
+
+func (api *LinksAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if !api.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="urlshort"`)
+		writeError(w, errorf(http.StatusUnauthorized, "missing or invalid bearer token"))
+		return
+	}
+
+	var status int
+	var body interface{}
+	var err error
+
+	rest := strings.TrimPrefix(r.URL.Path, LINKS_PREFIX)
+	switch {
+
+	case rest == "" || rest == "/":
+		switch r.Method {
+		case http.MethodGet:
+			status, body, err = api.list()
+		case http.MethodPost:
+			status, body, err = api.create(r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			err = errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+
+	case strings.HasPrefix(rest, "/"):
+		switch r.Method {
+		case http.MethodGet:
+			status, body, err = api.get(rest)
+		case http.MethodPut:
+			status, body, err = api.put(rest, r)
+		case http.MethodDelete:
+			status, body, err = api.delete(rest)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			err = errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+
+	default:
+		err = errorf(http.StatusNotFound, "not found")
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, status, body)
+}
+
+// authorized reports whether the request carries the Token. No
+// request is authorized if the Token is empty.
+func (api *LinksAPI) authorized(r *http.Request) bool {
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if api.Token == "" || token == r.Header.Get("Authorization") {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(api.Token)) == 1
+}
+
+func (api *LinksAPI) list() (int, interface{}, error) {
+
+	mapping, err := api.Store.List()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	links := make([]URL, 0, len(mapping))
+	for p, u := range mapping {
+		links = append(links, URL{Path: p, URL: u})
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Path < links[j].Path })
+
+	return http.StatusOK, map[string][]URL{"links": links}, nil
+}
+
+func (api *LinksAPI) get(p string) (int, interface{}, error) {
+
+	if err := validatePath(p); err != nil {
+		return 0, nil, err
+	}
+	u, err := api.Store.Lookup(p)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil, errorf(http.StatusNotFound, "no link for path %q", p)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, URL{Path: p, URL: u}, nil
+}
+
+func (api *LinksAPI) create(r *http.Request) (int, interface{}, error) {
+
+	var link URL
+	if err := readJSON(r, &link); err != nil {
+		return 0, nil, err
+	}
+	if err := api.validate(link); err != nil {
+		return 0, nil, err
+	}
+
+	created, err := api.Store.PutIfAbsent(link.Path, link.URL)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !created {
+		return 0, nil, errorf(http.StatusConflict, "path %q already exists", link.Path)
+	}
+
+	return http.StatusCreated, link, nil
+}
+
+func (api *LinksAPI) put(p string, r *http.Request) (int, interface{}, error) {
+
+	var link URL
+	if err := readJSON(r, &link); err != nil {
+		return 0, nil, err
+	}
+	if link.Path != "" && link.Path != p {
+		return 0, nil, errorf(http.StatusBadRequest, "path %q does not match the path of the request %q", link.Path, p)
+	}
+	link.Path = p
+	if err := api.validate(link); err != nil {
+		return 0, nil, err
+	}
+
+	created, err := api.Store.Put(link.Path, link.URL)
+	if err != nil {
+		return 0, nil, err
+	}
+	if created {
+		return http.StatusCreated, link, nil
+	}
+
+	return http.StatusOK, link, nil
+}
+
+func (api *LinksAPI) delete(p string) (int, interface{}, error) {
+
+	if err := validatePath(p); err != nil {
+		return 0, nil, err
+	}
+	err := api.Store.Delete(p)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil, errorf(http.StatusNotFound, "no link for path %q", p)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusNoContent, nil, nil
+}
+
+// validate checks the syntax of a link's path and URL, and that
+// its path is neither reserved nor mapped by the Others.
+func (api *LinksAPI) validate(link URL) error {
+
+	if err := validatePath(link.Path); err != nil {
+		return err
+	}
+	if err := validateURL(link.URL); err != nil {
+		return err
+	}
+
+	for _, reserved := range api.Reserved {
+		if link.Path == reserved || strings.HasPrefix(link.Path, reserved+"/") {
+			return errorf(http.StatusConflict, "path %q is reserved", link.Path)
+		}
+	}
+	if api.Others == nil {
+		return nil
+	}
+	if _, err := api.Others.Lookup(link.Path); err == nil {
+		return errorf(http.StatusConflict, "path %q is already mapped by another source", link.Path)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// validatePath checks that a path is absolute and clean, e.g.
+// "/docs/go", and made only of characters that need no escaping
+// in a URL path.
+func validatePath(p string) error {
+
+	if p == "" {
+		return errorf(http.StatusBadRequest, "missing path")
+	}
+	if !strings.HasPrefix(p, "/") || p == "/" || path.Clean(p) != p {
+		return errorf(http.StatusBadRequest, "invalid path %q (expected a clean absolute path such as /docs)", p)
+	}
+	for _, r := range p {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("/-._~", r)) {
+			return errorf(http.StatusBadRequest, "invalid character %q in path %q", r, p)
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks that a URL is an absolute http(s) URL.
+func validateURL(rawURL string) error {
+
+	if rawURL == "" {
+		return errorf(http.StatusBadRequest, "missing url")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errorf(http.StatusBadRequest, "invalid url %q (expected an absolute http or https URL)", rawURL)
+	}
+
+	return nil
+}
+
+// readJSON decodes the JSON body of the request into v,
+// rejecting unknown fields.
+func readJSON(r *http.Request, v interface{}) error {
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err != nil || mt != "application/json" {
+			return errorf(http.StatusUnsupportedMediaType, "unsupported content type %q (expected application/json)", ct)
+		}
+	}
+
+	dec := json.NewDecoder(io.LimitReader(r.Body, MAX_LINK_BODY))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON body: %v", err)
+	}
+
+	return nil
+}
+
+// writeJSON writes the body as JSON with the status. A nil body
+// is not written.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the error as JSON with its status. Errors
+// other than an apiError are logged and reported as internal
+// server errors, without their details.
+func writeError(w http.ResponseWriter, err error) {
+
+	var e *apiError
+	if !errors.As(err, &e) {
+		log.Printf("api: %v", err)
+		e = &apiError{http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)}
+	}
+
+	writeJSON(w, e.status, map[string]string{"error": e.message})
+}
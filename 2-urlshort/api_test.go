@@ -0,0 +1,281 @@
+package urlshort
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const testToken = "secret"
+
+// newTestBoltStore returns a BoltStore of a new database.
+func newTestBoltStore(t *testing.T) *BoltStore {
+
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewBoltStore(db)
+}
+
+// newTestAPI serves a LinksAPI of a new database, in which
+// /taken is mapped by another store and /status is reserved.
+func newTestAPI(t *testing.T) (*httptest.Server, *BoltStore) {
+
+	t.Helper()
+	store := newTestBoltStore(t)
+	others := MapStore{"/taken": "https://example.com/taken"}
+	api := NewLinksAPI(store, others, testToken)
+	api.Reserved = append(api.Reserved, "/status")
+
+	mux := http.NewServeMux()
+	mux.Handle(LINKS_PREFIX, api)
+	mux.Handle(LINKS_PREFIX+"/", api)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, store
+}
+
+// request sends a request with the bearer token, if any, and
+// returns the status and body of the response.
+func request(t *testing.T, server *httptest.Server, token string, method string, path string, body string) (int, string) {
+
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(data)
+}
+
+func TestLinksAPIAuth(t *testing.T) {
+
+	server, _ := newTestAPI(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"missing token", ""},
+		{"wrong token", "Bearer wrong"},
+		{"not a bearer token", testToken},
+		{"other scheme", "Basic " + testToken},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+LINKS_PREFIX, nil)
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: got the status %d, want %d", test.name, resp.StatusCode, http.StatusUnauthorized)
+		}
+		if resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: got no WWW-Authenticate header", test.name)
+		}
+	}
+
+	// No request is authorized without a token.
+	api := NewLinksAPI(newTestBoltStore(t), nil, "")
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, LINKS_PREFIX, nil)
+	req.Header.Set("Authorization", "Bearer ")
+	api.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("empty token: got the status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLinksAPI(t *testing.T) {
+
+	server, store := newTestAPI(t)
+
+	// Each step runs against the links left by those before it.
+	steps := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{"POST", "/api/links", `{"path": "/docs", "url": "https://go.dev/doc"}`, 201, `"path":"/docs"`},
+		{"POST", "/api/links", `{"path": "/docs", "url": "https://example.com"}`, 409, `already exists`},
+		{"POST", "/api/links", `{"path": "/go", "url": "https://go.dev"}`, 201, ``},
+
+		// Paths and URLs are validated.
+		{"POST", "/api/links", `{"path": "docs", "url": "https://go.dev"}`, 400, `invalid path`},
+		{"POST", "/api/links", `{"path": "/a/../b", "url": "https://go.dev"}`, 400, `invalid path`},
+		{"POST", "/api/links", `{"path": "/", "url": "https://go.dev"}`, 400, `invalid path`},
+		{"POST", "/api/links", `{"path": "/a b", "url": "https://go.dev"}`, 400, `invalid character`},
+		{"POST", "/api/links", `{"path": "/café", "url": "https://go.dev"}`, 400, `invalid character`},
+		{"POST", "/api/links", `{"url": "https://go.dev"}`, 400, `missing path`},
+		{"POST", "/api/links", `{"path": "/a"}`, 400, `missing url`},
+		{"POST", "/api/links", `{"path": "/a", "url": "ftp://go.dev"}`, 400, `invalid url`},
+		{"POST", "/api/links", `{"path": "/a", "url": "/relative"}`, 400, `invalid url`},
+		{"POST", "/api/links", `{"path": "/a", "url": "https://go.dev", "note": "x"}`, 400, `invalid JSON`},
+		{"POST", "/api/links", `not json`, 400, `invalid JSON`},
+
+		// Links may not take over reserved paths or the paths of
+		// other stores.
+		{"POST", "/api/links", `{"path": "/api", "url": "https://go.dev"}`, 409, `reserved`},
+		{"POST", "/api/links", `{"path": "/api/links", "url": "https://go.dev"}`, 409, `reserved`},
+		{"POST", "/api/links", `{"path": "/status", "url": "https://go.dev"}`, 409, `reserved`},
+		{"POST", "/api/links", `{"path": "/status/x", "url": "https://go.dev"}`, 409, `reserved`},
+		{"POST", "/api/links", `{"path": "/statuses", "url": "https://go.dev"}`, 201, ``},
+		{"POST", "/api/links", `{"path": "/taken", "url": "https://go.dev"}`, 409, `mapped by another source`},
+		{"PUT", "/api/links/taken", `{"url": "https://go.dev"}`, 409, `mapped by another source`},
+
+		{"GET", "/api/links", ``, 200, `{"links":[{"path":"/docs","url":"https://go.dev/doc"},{"path":"/go","url":"https://go.dev"},{"path":"/statuses","url":"https://go.dev"}]}`},
+		{"GET", "/api/links/docs", ``, 200, `{"path":"/docs","url":"https://go.dev/doc"}`},
+		{"GET", "/api/links/missing", ``, 404, `no link`},
+
+		// PUT replaces a link or creates a new one.
+		{"PUT", "/api/links/docs", `{"url": "https://pkg.go.dev"}`, 200, `"url":"https://pkg.go.dev"`},
+		{"PUT", "/api/links/blog", `{"url": "https://go.dev/blog"}`, 201, ``},
+		{"PUT", "/api/links/blog", `{"path": "/other", "url": "https://go.dev/blog"}`, 400, `does not match`},
+		{"GET", "/api/links/docs", ``, 200, `"url":"https://pkg.go.dev"`},
+
+		{"DELETE", "/api/links/docs", ``, 204, ``},
+		{"DELETE", "/api/links/docs", ``, 404, `no link`},
+		{"GET", "/api/links/docs", ``, 404, `no link`},
+
+		{"PATCH", "/api/links/go", `{"url": "https://go.dev"}`, 405, `not allowed`},
+		{"DELETE", "/api/links", ``, 405, `not allowed`},
+		{"GET", "/api/linksmore", ``, 404, `not found`},
+	}
+
+	for _, step := range steps {
+		status, body := request(t, server, testToken, step.method, step.path, step.body)
+		if status != step.status || !strings.Contains(body, step.want) {
+			t.Errorf("%s %s %s: got %d %s, want %d with %s",
+				step.method, step.path, step.body, status, body, step.status, step.want)
+		}
+	}
+
+	mapping, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/go":       "https://go.dev",
+		"/statuses": "https://go.dev",
+		"/blog":     "https://go.dev/blog",
+	}
+	if !equalMappings(mapping, want) {
+		t.Errorf("got the links %v, want %v", mapping, want)
+	}
+}
+
+func TestLinksAPIContentType(t *testing.T) {
+
+	server, _ := newTestAPI(t)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+LINKS_PREFIX, strings.NewReader(`{"path": "/a", "url": "https://go.dev"}`))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("got the status %d, want %d", resp.StatusCode, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestLinksAPIConcurrentCreate(t *testing.T) {
+
+	server, _ := newTestAPI(t)
+
+	// Of many requests to create the same path at once, only one
+	// succeeds.
+	const n = 20
+	statuses := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPost, server.URL+LINKS_PREFIX, strings.NewReader(`{"path": "/race", "url": "https://go.dev"}`))
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	count := map[int]int{}
+	for status := range statuses {
+		count[status]++
+	}
+	if count[http.StatusCreated] != 1 || count[http.StatusConflict] != n-1 {
+		t.Errorf("got the statuses %v, want one %d and the rest %d", count, http.StatusCreated, http.StatusConflict)
+	}
+}
+
+func TestWriteErrorHidesDetails(t *testing.T) {
+
+	rec := httptest.NewRecorder()
+	writeError(rec, io.ErrUnexpectedEOF)
+
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusInternalServerError || body["error"] != http.StatusText(http.StatusInternalServerError) {
+		t.Errorf("got %d %v, want an internal server error without details", rec.Code, body)
+	}
+}
+
+func equalMappings(a, b map[string]string) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for path, url := range a {
+		if other, ok := b[path]; !ok || other != url {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,65 +1,47 @@
 package urlshort
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"net/http"
 
-	"gopkg.in/yaml.v2"
-
 	bolt "go.etcd.io/bbolt"
 )
 
-// MapHandler will return an http.HandlerFunc (which also
-// implements http.Handler) that will attempt to map any
-// paths (keys in the map) to their corresponding URL (values
-// that each key in the map points to, in string format).
-// If the path is not provided in the map, then the fallback
-// http.Handler will be called instead.
-func MapHandler(pathsToUrls map[string]string, fallback http.Handler) http.HandlerFunc {
+// Handler will return an http.HandlerFunc (which also
+// implements http.Handler) that will attempt to look up the
+// path of each request in the store and redirect to its URL.
+// If the store has no URL for the path, then the fallback
+// http.Handler will be called instead. If the store fails, the
+// error is logged and reported as an internal server error.
+//
+// See Chain to look paths up in several stores.
+func Handler(store Store, fallback http.Handler) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		if path, ok := pathsToUrls[r.URL.String()]; ok {
-			http.Redirect(w, r, path, http.StatusFound)
-		} else {
+		url, err := store.Lookup(r.URL.Path)
+		switch {
+		case err == nil:
+			http.Redirect(w, r, url, http.StatusFound)
+		case errors.Is(err, ErrNotFound):
 			fallback.ServeHTTP(w, r)
+		default:
+			log.Printf("lookup %s: %v", r.URL.Path, err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		}
 
 	}
 }
 
-// T is used to unmarshal the YAML data.
-//
-// YAML is expected to be in the format:
-//
-//     - path: /some-path
-//       url: https://www.some-url.com/demo
-//
-type T struct {
-	Path string `yaml:"path"`
-	URL  string `yaml:"url"`
-}
-
-// YAMLtoMap will parse the provided YAML data and return it
-// in the form of a Map.
-func YAMLtoMap(yml []byte) map[string]string {
-
-	t := []T{}
-
-	err := yaml.Unmarshal(yml, &t)
-	if err != nil {
-		log.Fatalf("error: %v", err)
-	}
-
-	result := make(map[string]string)
-	for _, entry := range t {
-		result[entry.Path] = entry.URL
-	}
-
-	return result
+// MapHandler will return an http.HandlerFunc (which also
+// implements http.Handler) that will attempt to map any
+// paths (keys in the map) to their corresponding URL (values
+// that each key in the map points to, in string format).
+// If the path is not provided in the map, then the fallback
+// http.Handler will be called instead.
+func MapHandler(pathsToUrls map[string]string, fallback http.Handler) http.HandlerFunc {
+	return Handler(MapStore(pathsToUrls), fallback)
 }
 
 // YAMLHandler will parse the provided YAML and then return
@@ -68,73 +50,18 @@ func YAMLtoMap(yml []byte) map[string]string {
 // URL. If the path is not provided in the YAML, then the
 // fallback http.Handler will be called instead.
 //
-// YAML is expected to be in the format:
-//
-//     - path: /some-path
-//       url: https://www.some-url.com/demo
-//
 // The only errors that can be returned all related to having
 // invalid YAML data.
 //
-// See MapHandler to create a similar http.HandlerFunc via
-// a mapping of paths to urls.
+// See YAMLStore for the format of the YAML data.
 func YAMLHandler(yml []byte, fallback http.Handler) (http.HandlerFunc, error) {
 
-	pathsToUrls := YAMLtoMap(yml)
-
-	return func(w http.ResponseWriter, r *http.Request) {
-
-		if path, ok := pathsToUrls[r.URL.String()]; ok {
-			http.Redirect(w, r, path, http.StatusFound)
-		} else {
-			fallback.ServeHTTP(w, r)
-		}
-
-	}, nil
-}
-
-// URL is used to unmarshal the records within `mapping` from
-//  the JSON data.
-//
-// URLs is used to unmarshal `mapping` from the JSON data.
-//
-// JSON is expected to be in the format:
-//
-//	{
-//			"mapping": [
-//				{
-//					"path": "..."
-//					"url": "..."
-//				},
-//				...
-//			]
-//	}
-//
-type URLs struct {
-	URLs []URL `json:"mapping"`
-}
-type URL struct {
-	Path string `json:"path"`
-	URL  string `json:"url"`
-}
-
-// JSONtoMap will parse the provided JSON data and return it
-// in the form of a Map.
-func JSONtoMap(jsn []byte) map[string]string {
-
-	u := URLs{}
-
-	err := json.Unmarshal(jsn, &u)
+	store, err := YAMLStore(yml)
 	if err != nil {
-		log.Fatalf("error: %v", err)
+		return nil, err
 	}
 
-	result := make(map[string]string)
-	for _, entry := range u.URLs {
-		result[entry.Path] = entry.URL
-	}
-
-	return result
+	return Handler(store, fallback), nil
 }
 
 // JSONHandler will parse the provided JSON and then return
@@ -143,86 +70,27 @@ func JSONtoMap(jsn []byte) map[string]string {
 // URL. If the path is not provided in the JSON, then the
 // fallback http.Handler will be called instead.
 //
-// JSON is expected to be in the format:
-//
-//	{
-//			"mapping": [
-//				{
-//					"path": "..."
-//					"url": "..."
-//				},
-//				...
-//			]
-//	}
-//
 // The only errors that can be returned all related to having
 // invalid JSON data.
 //
-// See MapHandler to create a similar http.HandlerFunc via
-// a mapping of paths to urls.
+// See JSONStore for the format of the JSON data.
 func JSONHandler(jsn []byte, fallback http.Handler) (http.HandlerFunc, error) {
 
-	pathsToUrls := JSONtoMap(jsn)
-
-	return func(w http.ResponseWriter, r *http.Request) {
-
-		if path, ok := pathsToUrls[r.URL.String()]; ok {
-			http.Redirect(w, r, path, http.StatusFound)
-		} else {
-			fallback.ServeHTTP(w, r)
-		}
-
-	}, nil
-}
-
-// JSONtoMap will access the provided BoltDB database and
-// return it's entries  in the form of a Map.
-func BoltDBtoMap(db *bolt.DB) map[string]string {
-
-	result := make(map[string]string)
-
-	db.View(func(tx *bolt.Tx) error {
-
-		b := tx.Bucket([]byte("mapping"))
-
-		fmt.Println("BoltDB entries:")
-		b.ForEach(func(k, v []byte) error {
-			fmt.Printf("%s: %s,\n", string(k), string(v))
-			result[string(k)] = string(v)
-			return nil
-		})
-
-		return errors.New("database failed during reading")
-	})
+	store, err := JSONStore(jsn)
+	if err != nil {
+		return nil, err
+	}
 
-	return result
+	return Handler(store, fallback), nil
 }
 
-// BoltDBHandler will parse the provided JSON and then return
-// an http.HandlerFunc (which also implements http.Handler)
-// that will attempt to map any paths to their corresponding
-// URL. If the path is not provided in the BoltDB, then the
-// fallback http.Handler will be called instead.
-//
-// BoltDB entries are encoded and must be written to the
-// database using golang.
+// BoltHandler will return an http.HandlerFunc (which also
+// implements http.Handler) that will attempt to map any paths
+// to their corresponding URL in the BoltDB database. If the
+// path is not in the database, then the fallback http.Handler
+// will be called instead.
 //
-// The only errors that can be returned all related to having
-// invalid BoltDB entries.
-//
-// See MapHandler to create a similar http.HandlerFunc via
-// a mapping of paths to urls.
+// See BoltStore for how the paths are kept in the database.
 func BoltHandler(blt *bolt.DB, fallback http.Handler) (http.HandlerFunc, error) {
-
-	pathsToUrls := BoltDBtoMap(blt)
-
-	return func(w http.ResponseWriter, r *http.Request) {
-
-		if path, ok := pathsToUrls[r.URL.String()]; ok {
-			http.Redirect(w, r, path, http.StatusFound)
-		} else {
-			fallback.ServeHTTP(w, r)
-		}
-
-	}, nil
+	return Handler(NewBoltStore(blt), fallback), nil
 }
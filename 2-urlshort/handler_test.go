@@ -0,0 +1,83 @@
+package urlshort
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "fallback")
+	})
+	store := Chain(
+		MapStore{"/docs": "https://go.dev/doc", "/query?q=1": "https://example.com/query"},
+		MapStore{"/docs": "https://example.com/docs", "/blog": "https://go.dev/blog"},
+	)
+	handler := Handler(store, fallback)
+
+	tests := []struct {
+		target   string
+		status   int
+		location string
+	}{
+		{"/docs", http.StatusFound, "https://go.dev/doc"},
+		{"/blog", http.StatusFound, "https://go.dev/blog"},
+		{"/missing", http.StatusOK, ""},
+		{"/", http.StatusOK, ""},
+
+		// Only the path of a request is looked up, so a query
+		// string neither stops a path from matching nor matches
+		// on its own.
+		{"/docs?utm_source=mail", http.StatusFound, "https://go.dev/doc"},
+		{"/query?q=1", http.StatusOK, ""},
+		{"/docs/", http.StatusOK, ""},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+
+		if rec.Code != test.status || rec.Header().Get("Location") != test.location {
+			t.Errorf("%s: got %d to %q, want %d to %q",
+				test.target, rec.Code, rec.Header().Get("Location"), test.status, test.location)
+		}
+		if test.location == "" && rec.Body.String() != "fallback" {
+			t.Errorf("%s: got %q, want the fallback", test.target, rec.Body.String())
+		}
+	}
+}
+
+func TestHandlerStoreError(t *testing.T) {
+
+	called := false
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	rec := httptest.NewRecorder()
+	Handler(failingStore{}, fallback)(rec, httptest.NewRequest(http.MethodGet, "/a", nil))
+
+	if rec.Code != http.StatusInternalServerError || called {
+		t.Errorf("got %d (fallback called: %v), want %d without the fallback",
+			rec.Code, called, http.StatusInternalServerError)
+	}
+}
+
+func TestYAMLHandler(t *testing.T) {
+
+	handler, err := YAMLHandler([]byte("- path: /a\n  url: https://a.example\n"), http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("YAMLHandler: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/a", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://a.example" {
+		t.Errorf("got %d to %q, want a redirect to https://a.example", rec.Code, rec.Header().Get("Location"))
+	}
+
+	if _, err := YAMLHandler([]byte("path: ["), http.NotFoundHandler()); err == nil {
+		t.Error("YAMLHandler of invalid YAML: got no error")
+	}
+}
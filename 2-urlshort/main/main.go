@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"time"
 	"urlshort"
 
 	bolt "go.etcd.io/bbolt"
@@ -20,6 +22,9 @@ Sources
 - https://golang.hotexamples.com/examples/http/-/Redirect/golang-redirect-function-examples.html
 - https://gobyexample.com/structs
 - https://suraj.io/post/golang-struct-tags-space/
+- https://go.dev/doc/effective_go#interfaces
+- https://pkg.go.dev/time#Ticker
+- https://developer.mozilla.org/en-US/docs/Web/HTTP/Status
 */
 
 func main() {
@@ -48,18 +53,40 @@ func main() {
 		"YAML file that maps a path to an HTTP address for redirecting",
 	)
 
+	var reload_interval time.Duration
+	flag.DurationVar(
+		&reload_interval,
+		"reload_interval",
+		2*time.Second,
+		"how often the JSON and YAML files are checked for changes to reload (0 to never reload)",
+	)
+
+	var api_token string
+	flag.StringVar(
+		&api_token,
+		"api_token",
+		os.Getenv("URLSHORT_API_TOKEN"),
+		"bearer token of the /api/links API to manage the bolt database's links (the API is off if empty; defaults to $URLSHORT_API_TOKEN)",
+	)
+
 	flag.Parse()
 
 	// Read in data from JSON file.
-	jsn, err := ioutil.ReadFile(json_file)
+	jsonStore, err := urlshort.JSONFileStore(json_file)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("%s: %v", json_file, err)
 	}
 
 	// Read in data from YAML file.
-	yml, err := ioutil.ReadFile(yaml_file)
+	yamlStore, err := urlshort.YAMLFileStore(yaml_file)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("%s: %v", yaml_file, err)
+	}
+
+	// Reload the files whenever they change.
+	if reload_interval > 0 {
+		go jsonStore.Watch(context.Background(), reload_interval)
+		go yamlStore.Watch(context.Background(), reload_interval)
 	}
 
 	// Read in data from BoltDB database.
@@ -69,37 +96,37 @@ func main() {
 	}
 	defer db.Close()
 
-	mux := defaultMux()
-
-	// Build the MapHandler using the mux as the fallback
-	pathsToUrls := map[string]string{
+	// Build the stores, each taking precedence over those after
+	// it in the chain.
+	pathsToUrls := urlshort.MapStore{
 		"/urlshort-godoc": "https://godoc.org/github.com/gophercises/urlshort",
 		"/yaml-godoc":     "https://godoc.org/gopkg.in/yaml.v2",
 	}
-	mapHandler := urlshort.MapHandler(pathsToUrls, mux)
-
-	// Build the YAMLHandler using the mapHandler as the
-	// fallback
-	yamlHandler, err := urlshort.YAMLHandler(yml, mapHandler)
-	if err != nil {
-		panic(err)
-	}
 
-	// Build the JSONHandler using the YAMLHandler as the
-	// fallback
-	jsonHandler, err := urlshort.JSONHandler(jsn, yamlHandler)
-	if err != nil {
-		panic(err)
+	boltStore := urlshort.NewBoltStore(db)
+	others := urlshort.Chain(jsonStore, yamlStore, pathsToUrls)
+
+	store := urlshort.Chain(boltStore, others)
+
+	// Build the Handler using the default mux as the fallback.
+	// The status and API endpoints are routed in front of it, so
+	// that no mapping, even one reloaded from a file, can take
+	// them over.
+	handler := http.NewServeMux()
+	handler.Handle("/", urlshort.Handler(store, defaultMux()))
+	handler.Handle("/status", urlshort.StatusHandler(jsonStore, yamlStore))
+
+	// Serve the API to manage the links in the BoltDB database,
+	// which may not take over the paths of the other stores.
+	if api_token != "" {
+		api := urlshort.NewLinksAPI(boltStore, others, api_token)
+		api.Reserved = append(api.Reserved, "/status")
+		handler.Handle(urlshort.LINKS_PREFIX, api)
+		handler.Handle(urlshort.LINKS_PREFIX+"/", api)
 	}
 
-	// Build the BoltHandler using the JSONHandler as the
-	// fallback
-	boltHandler, err := urlshort.BoltHandler(db, jsonHandler)
-	if err != nil {
-		panic(err)
-	}
 	fmt.Println("Starting the server on :8080")
-	http.ListenAndServe(":8080", boltHandler)
+	log.Fatal(http.ListenAndServe(":8080", handler))
 }
 
 func defaultMux() *http.ServeMux {
@@ -0,0 +1,109 @@
+package urlshort
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by a Store that has no URL for a path.
+var ErrNotFound = errors.New("path not found")
+
+// Store maps paths to the URLs they redirect to.
+//
+// Lookup returns the URL of the path, or ErrNotFound if the
+// Store has none.
+type Store interface {
+	Lookup(path string) (string, error)
+}
+
+// Lister is a Store that can list every path it maps.
+type Lister interface {
+	Store
+	List() (map[string]string, error)
+}
+
+// Editor is a Store whose mapping can be changed. Put maps a
+// path to a URL, replacing any URL it had, and PutIfAbsent maps
+// it only if it has none; both report whether the path was
+// created, checking for it and writing it as one change. Delete
+// removes a path, returning ErrNotFound if the Store has none.
+type Editor interface {
+	Store
+	Put(path string, url string) (bool, error)
+	PutIfAbsent(path string, url string) (bool, error)
+	Delete(path string) error
+}
+
+// MapStore is a Store of a fixed mapping of paths (keys in the
+// map) to their corresponding URL (values that each key in the
+// map points to, in string format).
+type MapStore map[string]string
+
+// Lookup returns the URL of the path.
+func (m MapStore) Lookup(path string) (string, error) {
+
+	url, ok := m[path]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return url, nil
+}
+
+// List returns a copy of the mapping.
+func (m MapStore) List() (map[string]string, error) {
+
+	result := make(map[string]string, len(m))
+	for path, url := range m {
+		result[path] = url
+	}
+
+	return result, nil
+}
+
+// ChainStore is a Store that looks a path up in each of its
+// Stores in turn, so that a Store takes precedence over those
+// after it.
+type ChainStore []Store
+
+// Chain returns a Store that looks a path up in each of the
+// stores in turn.
+func Chain(stores ...Store) ChainStore {
+	return ChainStore(stores)
+}
+
+// Lookup returns the URL of the path in the first Store that has
+// one. Any error other than ErrNotFound stops the lookup.
+func (c ChainStore) Lookup(path string) (string, error) {
+
+	for _, store := range c {
+		url, err := store.Lookup(path)
+		if !errors.Is(err, ErrNotFound) {
+			return url, err
+		}
+	}
+
+	return "", ErrNotFound
+}
+
+// List returns the mapping of every Store that is a Lister,
+// where a Store's paths take precedence over those of the
+// Stores after it.
+func (c ChainStore) List() (map[string]string, error) {
+
+	result := make(map[string]string)
+	for i := len(c) - 1; i >= 0; i-- {
+		lister, ok := c[i].(Lister)
+		if !ok {
+			continue
+		}
+		mapping, err := lister.List()
+		if err != nil {
+			return nil, err
+		}
+		for path, url := range mapping {
+			result[path] = url
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package urlshort
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BOLT_BUCKET is the bucket of a BoltDB database that maps
+// paths to URLs.
+const BOLT_BUCKET = "mapping"
+
+// BoltStore is a Store of the paths in the BOLT_BUCKET of a
+// BoltDB database, where each key is a path and its value the
+// URL. Paths are read from the database as they are looked up,
+// so changes to it, e.g. through a LinksAPI, take effect at once.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore returns a Store of the mapping in the database.
+func NewBoltStore(db *bolt.DB) *BoltStore {
+	return &BoltStore{db: db}
+}
+
+// Lookup returns the URL of the path.
+func (s *BoltStore) Lookup(path string) (string, error) {
+
+	var url string
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket([]byte(BOLT_BUCKET))
+		if b == nil {
+			return ErrNotFound
+		}
+		v := b.Get([]byte(path))
+		if v == nil {
+			return ErrNotFound
+		}
+		url = string(v)
+
+		return nil
+	})
+
+	return url, err
+}
+
+// List returns every path in the database.
+func (s *BoltStore) List() (map[string]string, error) {
+
+	result := make(map[string]string)
+	err := s.db.View(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket([]byte(BOLT_BUCKET))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			result[string(k)] = string(v)
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// Put maps the path to the URL, creating the bucket if the
+// database has none yet, and reports whether the path is new.
+func (s *BoltStore) Put(path string, url string) (bool, error) {
+	return s.put(path, url, true)
+}
+
+// PutIfAbsent maps the path to the URL unless the database
+// already has the path, and reports whether it did.
+func (s *BoltStore) PutIfAbsent(path string, url string) (bool, error) {
+	return s.put(path, url, false)
+}
+
+// put maps the path to the URL, replacing any URL it had only if
+// replace is set, within one transaction so that concurrent
+// changes to the path cannot interleave.
+func (s *BoltStore) put(path string, url string, replace bool) (bool, error) {
+
+	created := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+
+		b, err := tx.CreateBucketIfNotExists([]byte(BOLT_BUCKET))
+		if err != nil {
+			return err
+		}
+
+		created = b.Get([]byte(path)) == nil
+		if !created && !replace {
+			return nil
+		}
+
+		return b.Put([]byte(path), []byte(url))
+	})
+
+	return created && err == nil, err
+}
+
+// Delete removes the path from the database.
+func (s *BoltStore) Delete(path string) error {
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket([]byte(BOLT_BUCKET))
+		if b == nil || b.Get([]byte(path)) == nil {
+			return ErrNotFound
+		}
+
+		return b.Delete([]byte(path))
+	})
+}
+
+// BoltDBtoMap will access the provided BoltDB database and
+// return it's entries  in the form of a Map.
+func BoltDBtoMap(db *bolt.DB) map[string]string {
+
+	result, err := NewBoltStore(db).List()
+	if err != nil {
+		fmt.Println("database failed during reading:", err)
+	}
+
+	fmt.Println("BoltDB entries:")
+	for path, url := range result {
+		fmt.Printf("%s: %s,\n", path, url)
+	}
+
+	return result
+}
@@ -0,0 +1,207 @@
+package urlshort
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// MAX_RELOAD_EVENTS is how many of its latest ReloadEvents a
+// FileStore keeps.
+const MAX_RELOAD_EVENTS = 20
+
+// ReloadEvent records an attempt to load the file of a
+// FileStore. Paths is the number of paths loaded, and Error is
+// set if the file could not be read or parsed.
+type ReloadEvent struct {
+	Time  time.Time `json:"time"`
+	Paths int       `json:"paths"`
+	Error string    `json:"error,omitempty"`
+}
+
+// FileStore is a Store of the mapping in a file, which it
+// reloads whenever the file changes while it is being watched.
+// A new mapping is swapped in whole, so each lookup sees either
+// the previous mapping or the new one. If the file cannot be
+// parsed, the previous mapping is kept.
+type FileStore struct {
+	path  string
+	parse func([]byte) (MapStore, error)
+
+	mu       sync.RWMutex
+	mapping  MapStore
+	modified time.Time
+	size     int64
+	events   []ReloadEvent
+}
+
+// NewFileStore returns a Store of the mapping in the file at the
+// specified path, which is parsed by the parse function, e.g.
+// YAMLStore. The only errors that can be returned are from
+// reading and parsing the file the first time.
+func NewFileStore(path string, parse func([]byte) (MapStore, error)) (*FileStore, error) {
+
+	s := &FileStore{path: path, parse: parse}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// YAMLFileStore returns a Store of the mapping in the YAML file
+// at the specified path. See YAMLStore for its format.
+func YAMLFileStore(path string) (*FileStore, error) {
+	return NewFileStore(path, YAMLStore)
+}
+
+// JSONFileStore returns a Store of the mapping in the JSON file
+// at the specified path. See JSONStore for its format.
+func JSONFileStore(path string) (*FileStore, error) {
+	return NewFileStore(path, JSONStore)
+}
+
+// Lookup returns the URL of the path in the current mapping.
+func (s *FileStore) Lookup(path string) (string, error) {
+
+	s.mu.RLock()
+	mapping := s.mapping
+	s.mu.RUnlock()
+
+	return mapping.Lookup(path)
+}
+
+// List returns a copy of the current mapping.
+func (s *FileStore) List() (map[string]string, error) {
+
+	s.mu.RLock()
+	mapping := s.mapping
+	s.mu.RUnlock()
+
+	return mapping.List()
+}
+
+// Reload reads and parses the file, swapping in its mapping. If
+// it fails, the previous mapping is kept and the error is
+// returned. Either way, a ReloadEvent is recorded.
+func (s *FileStore) Reload() error {
+
+	info, err := os.Stat(s.path)
+	var mapping MapStore
+	if err == nil {
+		var data []byte
+		if data, err = os.ReadFile(s.path); err == nil {
+			mapping, err = s.parse(data)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := ReloadEvent{Time: time.Now()}
+	if info != nil {
+		// A file that fails to parse is not retried until it
+		// changes again.
+		s.modified, s.size = info.ModTime(), info.Size()
+	}
+	if err != nil {
+		event.Error = err.Error()
+		event.Paths = len(s.mapping)
+	} else {
+		s.mapping = mapping
+		event.Paths = len(mapping)
+	}
+	s.events = append(s.events, event)
+	if len(s.events) > MAX_RELOAD_EVENTS {
+		s.events = s.events[len(s.events)-MAX_RELOAD_EVENTS:]
+	}
+
+	return err
+}
+
+// changed reports whether the file was modified since it was
+// last loaded.
+func (s *FileStore) changed() bool {
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return !info.ModTime().Equal(s.modified) || info.Size() != s.size
+}
+
+// Watch checks the file for changes at each interval, reloading
+// it when it has changed, until the context is done. Failed
+// reloads are logged.
+func (s *FileStore) Watch(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.changed() {
+				continue
+			}
+			if err := s.Reload(); err != nil {
+				log.Printf("reload %s: %v (keeping the previous mapping)", s.path, err)
+			} else {
+				log.Printf("reloaded %s", s.path)
+			}
+		}
+	}
+}
+
+// FileStatus is the state of a FileStore, as reported by
+// StatusHandler.
+type FileStatus struct {
+	Path     string        `json:"path"`
+	Paths    int           `json:"paths"`
+	Modified time.Time     `json:"modified"`
+	Events   []ReloadEvent `json:"events"`
+}
+
+// Status returns the state of the FileStore, with its latest
+// ReloadEvents.
+func (s *FileStore) Status() FileStatus {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return FileStatus{
+		Path:     s.path,
+		Paths:    len(s.mapping),
+		Modified: s.modified,
+		Events:   append([]ReloadEvent(nil), s.events...),
+	}
+}
+
+// StatusHandler will return an http.HandlerFunc that writes the
+// Status of each of the stores as JSON.
+func StatusHandler(stores ...*FileStore) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		statuses := make([]FileStatus, len(stores))
+		for i, store := range stores {
+			statuses[i] = store.Status()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(map[string][]FileStatus{"files": statuses})
+
+	}
+}
@@ -0,0 +1,224 @@
+package urlshort
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeYAML writes a YAML mapping of the paths to their URLs to
+// the file, moving its modification time forward so that the
+// change is seen even within the resolution of the file system.
+func writeYAML(t *testing.T, path string, mapping map[string]string) {
+
+	t.Helper()
+	var yml strings.Builder
+	for p, url := range mapping {
+		fmt.Fprintf(&yml, "- path: %s\n  url: %s\n", p, url)
+	}
+	writeFile(t, path, yml.String())
+}
+
+func writeFile(t *testing.T, path string, content string) {
+
+	t.Helper()
+	modified := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		modified = info.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestFileStore(t *testing.T, mapping map[string]string) (*FileStore, string) {
+
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "paths.yaml")
+	writeYAML(t, path, mapping)
+
+	store, err := YAMLFileStore(path)
+	if err != nil {
+		t.Fatalf("YAMLFileStore: %v", err)
+	}
+
+	return store, path
+}
+
+func lookup(store Store, path string) string {
+
+	url, err := store.Lookup(path)
+	if err != nil {
+		return err.Error()
+	}
+
+	return url
+}
+
+func TestFileStoreReload(t *testing.T) {
+
+	store, path := newTestFileStore(t, map[string]string{"/a": "https://a.example"})
+	if store.changed() {
+		t.Error("the file changed before it was written again")
+	}
+
+	writeYAML(t, path, map[string]string{"/b": "https://b.example"})
+	if !store.changed() {
+		t.Fatal("the file did not change once it was written")
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if got := lookup(store, "/b"); got != "https://b.example" {
+		t.Errorf("got %q for /b, want the reloaded URL", got)
+	}
+	if _, err := store.Lookup("/a"); err != ErrNotFound {
+		t.Errorf("got error %v for a removed path, want %v", err, ErrNotFound)
+	}
+	if store.changed() {
+		t.Error("the file changed after it was reloaded")
+	}
+}
+
+func TestFileStoreWatch(t *testing.T) {
+
+	store, path := newTestFileStore(t, map[string]string{"/a": "https://a.example"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, time.Millisecond)
+
+	writeYAML(t, path, map[string]string{"/a": "https://a2.example"})
+	for start := time.Now(); lookup(store, "/a") != "https://a2.example"; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("the changed file was not reloaded")
+		}
+	}
+}
+
+func TestFileStoreKeepsMappingOnError(t *testing.T) {
+
+	store, path := newTestFileStore(t, map[string]string{"/a": "https://a.example"})
+
+	writeFile(t, path, "- path: [unclosed\n")
+	if err := store.Reload(); err == nil {
+		t.Fatal("Reload of invalid YAML: got no error")
+	}
+	if got := lookup(store, "/a"); got != "https://a.example" {
+		t.Errorf("got %q for /a, want the previous mapping to be kept", got)
+	}
+	if store.changed() {
+		t.Error("a file that failed to parse is retried before it changes")
+	}
+
+	status := store.Status()
+	last := status.Events[len(status.Events)-1]
+	if last.Error == "" || last.Paths != 1 || status.Paths != 1 {
+		t.Errorf("got the status %+v, want the error with the previous mapping", status)
+	}
+
+	os.Remove(path)
+	if err := store.Reload(); err == nil {
+		t.Error("Reload of a missing file: got no error")
+	}
+	if got := lookup(store, "/a"); got != "https://a.example" {
+		t.Errorf("got %q for /a once the file was removed, want the previous mapping", got)
+	}
+}
+
+func TestFileStoreEvents(t *testing.T) {
+
+	store, path := newTestFileStore(t, map[string]string{"/0": "https://example.com"})
+
+	// The Nth reload has N+1 paths, so that the events can be told
+	// apart.
+	const reloads = MAX_RELOAD_EVENTS + 5
+	mapping := map[string]string{"/0": "https://example.com"}
+	for i := 1; i <= reloads; i++ {
+		mapping[fmt.Sprintf("/%d", i)] = "https://example.com"
+		writeYAML(t, path, mapping)
+		if err := store.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events := store.Status().Events
+	if len(events) != MAX_RELOAD_EVENTS {
+		t.Fatalf("got %d events, want the latest %d", len(events), MAX_RELOAD_EVENTS)
+	}
+	if first, last := events[0].Paths, events[len(events)-1].Paths; first != reloads+2-MAX_RELOAD_EVENTS || last != reloads+1 {
+		t.Errorf("got events from %d to %d paths, want from %d to %d",
+			first, last, reloads+2-MAX_RELOAD_EVENTS, reloads+1)
+	}
+}
+
+func TestFileStoreConcurrentLookup(t *testing.T) {
+
+	// Each mapping maps both paths to URLs of the same version, so
+	// a lookup of a mapping swapped in part would be seen.
+	versions := []map[string]string{
+		{"/a": "https://example.com/a1", "/b": "https://example.com/b1"},
+		{"/a": "https://example.com/a2", "/b": "https://example.com/b2"},
+	}
+	store, path := newTestFileStore(t, versions[0])
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				mapping, _ := store.List()
+				a, b := mapping["/a"], mapping["/b"]
+				if a[len(a)-1] != b[len(b)-1] {
+					t.Errorf("got a mixed mapping %v", mapping)
+					return
+				}
+				lookup(store, "/a")
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		writeYAML(t, path, versions[i%2])
+		if err := store.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestStatusHandler(t *testing.T) {
+
+	store, path := newTestFileStore(t, map[string]string{"/a": "https://a.example"})
+
+	rec := httptest.NewRecorder()
+	StatusHandler(store)(rec, httptest.NewRequest("GET", "/status", nil))
+
+	var body map[string][]FileStatus
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	files := body["files"]
+	if len(files) != 1 || files[0].Path != path || files[0].Paths != 1 || len(files[0].Events) != 1 {
+		t.Errorf("got the status %+v", body)
+	}
+}
@@ -0,0 +1,77 @@
+package urlshort
+
+import (
+	"encoding/json"
+	"log"
+)
+
+// URL is used to unmarshal the records within `mapping` from
+//  the JSON data.
+//
+// URLs is used to unmarshal `mapping` from the JSON data.
+//
+// JSON is expected to be in the format:
+//
+//	{
+//			"mapping": [
+//				{
+//					"path": "..."
+//					"url": "..."
+//				},
+//				...
+//			]
+//	}
+//
+type URLs struct {
+	URLs []URL `json:"mapping"`
+}
+type URL struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// JSONStore will parse the provided JSON data and return it as
+// a MapStore.
+//
+// JSON is expected to be in the format:
+//
+//	{
+//			"mapping": [
+//				{
+//					"path": "..."
+//					"url": "..."
+//				},
+//				...
+//			]
+//	}
+//
+// The only errors that can be returned all related to having
+// invalid JSON data.
+func JSONStore(jsn []byte) (MapStore, error) {
+
+	u := URLs{}
+
+	err := json.Unmarshal(jsn, &u)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(MapStore)
+	for _, entry := range u.URLs {
+		result[entry.Path] = entry.URL
+	}
+
+	return result, nil
+}
+
+// JSONtoMap will parse the provided JSON data and return it
+// in the form of a Map.
+func JSONtoMap(jsn []byte) map[string]string {
+
+	store, err := JSONStore(jsn)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	return store
+}
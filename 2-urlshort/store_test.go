@@ -0,0 +1,149 @@
+package urlshort
+
+import (
+	"errors"
+	"testing"
+)
+
+// failingStore is a Store whose lookups fail.
+type failingStore struct{}
+
+var errLookup = errors.New("lookup failed")
+
+func (failingStore) Lookup(path string) (string, error) {
+	return "", errLookup
+}
+
+func TestChainStoreLookup(t *testing.T) {
+
+	first := MapStore{"/a": "https://first.example/a", "/b": "https://first.example/b"}
+	second := MapStore{"/b": "https://second.example/b", "/c": "https://second.example/c"}
+
+	tests := []struct {
+		name  string
+		chain ChainStore
+		path  string
+		url   string
+		err   error
+	}{
+		{"only in the first", Chain(first, second), "/a", "https://first.example/a", nil},
+		{"in both", Chain(first, second), "/b", "https://first.example/b", nil},
+		{"in both, reversed", Chain(second, first), "/b", "https://second.example/b", nil},
+		{"only in the second", Chain(first, second), "/c", "https://second.example/c", nil},
+		{"in neither", Chain(first, second), "/d", "", ErrNotFound},
+		{"empty chain", Chain(), "/a", "", ErrNotFound},
+		{"nested chain", Chain(Chain(MapStore{}), Chain(second)), "/c", "https://second.example/c", nil},
+		{"failure after a match", Chain(first, failingStore{}), "/a", "https://first.example/a", nil},
+		{"failure before a match", Chain(failingStore{}, first), "/a", "", errLookup},
+		{"failure without a match", Chain(first, failingStore{}), "/d", "", errLookup},
+	}
+
+	for _, test := range tests {
+		url, err := test.chain.Lookup(test.path)
+		if url != test.url || !errors.Is(err, test.err) {
+			t.Errorf("%s: got %q, %v, want %q, %v", test.name, url, err, test.url, test.err)
+		}
+	}
+}
+
+func TestChainStoreList(t *testing.T) {
+
+	chain := Chain(
+		MapStore{"/a": "https://first.example/a"},
+		failingStore{},
+		MapStore{"/a": "https://second.example/a", "/b": "https://second.example/b"},
+	)
+
+	// Stores that cannot list their paths are skipped.
+	mapping, err := chain.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := map[string]string{"/a": "https://first.example/a", "/b": "https://second.example/b"}
+	if !equalMappings(mapping, want) {
+		t.Errorf("got %v, want %v", mapping, want)
+	}
+}
+
+func TestMapStoreListCopies(t *testing.T) {
+
+	store := MapStore{"/a": "https://a.example"}
+
+	mapping, _ := store.List()
+	mapping["/a"] = "https://changed.example"
+	if got := lookup(store, "/a"); got != "https://a.example" {
+		t.Errorf("changing the list changed the store to %q", got)
+	}
+}
+
+func TestBoltStore(t *testing.T) {
+
+	store := newTestBoltStore(t)
+
+	// A database without the bucket has no paths.
+	if _, err := store.Lookup("/a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup in an empty database: got error %v, want %v", err, ErrNotFound)
+	}
+	if mapping, err := store.List(); err != nil || len(mapping) != 0 {
+		t.Errorf("List of an empty database: got %v, %v", mapping, err)
+	}
+	if err := store.Delete("/a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete in an empty database: got error %v, want %v", err, ErrNotFound)
+	}
+
+	steps := []struct {
+		name    string
+		put     func(path string, url string) (bool, error)
+		path    string
+		url     string
+		created bool
+		want    string
+	}{
+		{"PutIfAbsent of a new path", store.PutIfAbsent, "/a", "https://a1.example", true, "https://a1.example"},
+		{"PutIfAbsent of an existing path", store.PutIfAbsent, "/a", "https://a2.example", false, "https://a1.example"},
+		{"Put of an existing path", store.Put, "/a", "https://a3.example", false, "https://a3.example"},
+		{"Put of a new path", store.Put, "/b", "https://b.example", true, "https://b.example"},
+	}
+
+	for _, step := range steps {
+		created, err := step.put(step.path, step.url)
+		if err != nil || created != step.created {
+			t.Errorf("%s: got %v, %v, want %v", step.name, created, err, step.created)
+		}
+		if got := lookup(store, step.path); got != step.want {
+			t.Errorf("%s: got %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	if err := store.Delete("/a"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	mapping, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"/b": "https://b.example"}; !equalMappings(mapping, want) {
+		t.Errorf("got %v once /a was deleted, want %v", mapping, want)
+	}
+}
+
+func TestParseStores(t *testing.T) {
+
+	yml := []byte("- path: /a\n  url: https://a.example\n- path: /b\n  url: https://b.example\n")
+	jsn := []byte(`{"mapping": [{"path": "/a", "url": "https://a.example"}, {"path": "/b", "url": "https://b.example"}]}`)
+	want := map[string]string{"/a": "https://a.example", "/b": "https://b.example"}
+
+	if store, err := YAMLStore(yml); err != nil || !equalMappings(store, want) {
+		t.Errorf("YAMLStore: got %v, %v, want %v", store, err, want)
+	}
+	if store, err := JSONStore(jsn); err != nil || !equalMappings(store, want) {
+		t.Errorf("JSONStore: got %v, %v, want %v", store, err, want)
+	}
+
+	if _, err := YAMLStore([]byte("path: [")); err == nil {
+		t.Error("YAMLStore of invalid YAML: got no error")
+	}
+	if _, err := JSONStore([]byte("{")); err == nil {
+		t.Error("JSONStore of invalid JSON: got no error")
+	}
+}
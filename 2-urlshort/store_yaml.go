@@ -0,0 +1,58 @@
+package urlshort
+
+import (
+	"log"
+
+	"gopkg.in/yaml.v2"
+)
+
+// T is used to unmarshal the YAML data.
+//
+// YAML is expected to be in the format:
+//
+//     - path: /some-path
+//       url: https://www.some-url.com/demo
+//
+type T struct {
+	Path string `yaml:"path"`
+	URL  string `yaml:"url"`
+}
+
+// YAMLStore will parse the provided YAML data and return it as
+// a MapStore.
+//
+// YAML is expected to be in the format:
+//
+//     - path: /some-path
+//       url: https://www.some-url.com/demo
+//
+// The only errors that can be returned all related to having
+// invalid YAML data.
+func YAMLStore(yml []byte) (MapStore, error) {
+
+	t := []T{}
+
+	err := yaml.Unmarshal(yml, &t)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(MapStore)
+	for _, entry := range t {
+		result[entry.Path] = entry.URL
+	}
+
+	return result, nil
+}
+
+// YAMLtoMap will parse the provided YAML data and return it
+// in the form of a Map.
+func YAMLtoMap(yml []byte) map[string]string {
+
+	store, err := YAMLStore(yml)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	return store
+}